@@ -0,0 +1,62 @@
+// Command privatize-proofs re-upload the payment proofs uploaded publicly before the private uploads
+// as authenticated assets, then destroy their public copies, run it once after deploying the private uploads
+//
+//	go run ./cmd/privatize-proofs
+//
+// with the same DB_SOURCE & cloudinary credentials as the service, it's safe to run again after a failure
+// as only the proofs which are still public are picked up
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	db "gubuk-service/db"
+	sqlc "gubuk-service/db/sqlc"
+	"gubuk-service/media"
+)
+
+func main() {
+	legacyPaymentProofs, err := db.Queries.ListLegacyPaymentProof(context.TODO())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	failed := 0
+	for _, v := range legacyPaymentProofs {
+		err = privatize(v)
+		if err != nil {
+			log.Printf("couldn't privatize payment proof %s: %v", v, err)
+			failed++
+		}
+	}
+
+	fmt.Printf("%d of %d payment proofs are privatized\n", len(legacyPaymentProofs)-failed, len(legacyPaymentProofs))
+	if failed > 0 {
+		log.Fatalf("%d payment proofs are still public, run the command again", failed)
+	}
+}
+
+// privatize replace a public payment proof by a private copy of it, the public one is only destroyed
+// once no transaction or payment submission refer to it anymore
+func privatize(paymentProof string) error {
+	privatePaymentProof, err := media.UploadRemotePrivateMedia("transaction", paymentProof)
+	if err != nil {
+		return err
+	}
+
+	err = db.Queries.ReplacePaymentProof(context.TODO(), sqlc.ReplacePaymentProofParams{
+		NewPaymentProof: privatePaymentProof,
+		OldPaymentProof: paymentProof,
+	})
+	if err != nil {
+		// the private copy isn't referred by anything, so it's destroyed to not leave it behind
+		if destroyErr := media.DestroyPrivateMedia(privatePaymentProof); destroyErr != nil {
+			log.Printf("couldn't destroy the private copy %s: %v", privatePaymentProof, destroyErr)
+		}
+		return err
+	}
+
+	return media.DestroyMedia(paymentProof)
+}
@@ -0,0 +1,52 @@
+// Command promote grant a role to an existing user, it's how the first admin is made on a fresh deployment:
+// register the account through the app as usual, then run
+//
+//	go run ./cmd/promote -username <username> [-role admin]
+//
+// with the same DB_SOURCE as the service
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+
+	db "gubuk-service/db"
+	sqlc "gubuk-service/db/sqlc"
+)
+
+func main() {
+	username := flag.String("username", "", "username of the user to promote")
+	role := flag.String("role", "admin", "role to grant to the user")
+	flag.Parse()
+
+	if *username == "" {
+		flag.Usage()
+		log.Fatal("username is required")
+	}
+
+	err := promote(*username, *role)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("%s is granted the %s role\n", *username, *role)
+}
+
+func promote(username string, role string) error {
+	user, err := db.Queries.GetUserByUsername(context.TODO(), username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("user %s is not exist", username)
+		}
+		return err
+	}
+
+	return db.Queries.AddUserRole(context.TODO(), sqlc.AddUserRoleParams{
+		UserID:   user.ID,
+		RoleName: role,
+	})
+}
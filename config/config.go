@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -15,6 +16,29 @@ var (
 	CloudinaryKey    string
 	CloudinarySecret string
 	DBSource         string
+
+	PaymentProvider      string
+	PaymentWebhookSecret string
+	MidtransServerKey    string
+	MidtransIsProduction bool
+
+	WebhookEchoEnabled bool
+
+	HouseApprovalRequired bool
+
+	// IdempotencyKeyTTL is how long a response is replayed for a repeated Idempotency-Key, 24 hours by default
+	IdempotencyKeyTTL time.Duration
+
+	// BookingPaymentTTL is how long a booking waits for it's payment before it's expired, 24 hours by default
+	BookingPaymentTTL time.Duration
+
+	MailDriver   string
+	MailFrom     string
+	MailDir      string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
 )
 
 func init() {
@@ -30,4 +54,31 @@ func init() {
 	CloudinaryKey = os.Getenv("CLOUDINARY_KEY")
 	CloudinarySecret = os.Getenv("CLOUDINARY_SECRET")
 	DBSource = os.Getenv("DB_SOURCE")
+
+	PaymentProvider = os.Getenv("PAYMENT_PROVIDER")
+	PaymentWebhookSecret = os.Getenv("PAYMENT_WEBHOOK_SECRET")
+	MidtransServerKey = os.Getenv("MIDTRANS_SERVER_KEY")
+	MidtransIsProduction = os.Getenv("MIDTRANS_IS_PRODUCTION") == "true"
+
+	WebhookEchoEnabled = os.Getenv("WEBHOOK_ECHO_ENABLED") == "true"
+
+	HouseApprovalRequired = os.Getenv("HOUSE_APPROVAL_REQUIRED") == "true"
+
+	IdempotencyKeyTTL, err = time.ParseDuration(os.Getenv("IDEMPOTENCY_KEY_TTL"))
+	if err != nil || IdempotencyKeyTTL <= 0 {
+		IdempotencyKeyTTL = 24 * time.Hour
+	}
+
+	BookingPaymentTTL, err = time.ParseDuration(os.Getenv("BOOKING_PAYMENT_TTL"))
+	if err != nil || BookingPaymentTTL <= 0 {
+		BookingPaymentTTL = 24 * time.Hour
+	}
+
+	MailDriver = os.Getenv("MAIL_DRIVER")
+	MailFrom = os.Getenv("MAIL_FROM")
+	MailDir = os.Getenv("MAIL_DIR")
+	SMTPHost = os.Getenv("SMTP_HOST")
+	SMTPPort = os.Getenv("SMTP_PORT")
+	SMTPUsername = os.Getenv("SMTP_USERNAME")
+	SMTPPassword = os.Getenv("SMTP_PASSWORD")
 }
@@ -0,0 +1,43 @@
+CREATE TABLE "payment_submissions" (
+  "id" uuid PRIMARY KEY,
+  "transaction_id" uuid NOT NULL,
+  "payment_proof" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  "transfer_date" timestamp NOT NULL,
+  "payment_method" varchar NOT NULL,
+  "notes" varchar NOT NULL,
+  "status" varchar NOT NULL,
+  "rejection_reason" varchar NOT NULL DEFAULT '',
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "payment_submissions" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id");
+
+CREATE INDEX ON "payment_submissions" ("transaction_id", "created_at");
+
+INSERT INTO "payment_submissions" (
+  "id",
+  "transaction_id",
+  "payment_proof",
+  "amount",
+  "transfer_date",
+  "payment_method",
+  "notes",
+  "status",
+  "created_at",
+  "updated_at"
+)
+SELECT
+  md5(random()::text || "id"::text)::uuid,
+  "id",
+  "payment_proof",
+  "total_payment",
+  "updated_at",
+  '',
+  '',
+  CASE WHEN "payment_status" = 'approved' THEN 'approved' ELSE 'waiting-approve' END,
+  "updated_at",
+  "updated_at"
+FROM "transactions"
+WHERE "payment_proof" <> '';
@@ -0,0 +1,15 @@
+CREATE TABLE "payment_charges" (
+  "id" uuid PRIMARY KEY,
+  "transaction_id" uuid NOT NULL,
+  "provider" varchar NOT NULL,
+  "provider_ref" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  "status" varchar NOT NULL,
+  "redirect_url" varchar NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "payment_charges" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id");
+
+CREATE INDEX ON "payment_charges" ("transaction_id");
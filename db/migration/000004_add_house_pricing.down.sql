@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS transaction_line_items;
+
+ALTER TABLE "homes"
+  DROP COLUMN IF EXISTS "security_deposit",
+  DROP COLUMN IF EXISTS "cleaning_fee",
+  DROP COLUMN IF EXISTS "service_fee",
+  DROP COLUMN IF EXISTS "weekly_discount",
+  DROP COLUMN IF EXISTS "monthly_discount",
+  DROP COLUMN IF EXISTS "tax_rate";
@@ -0,0 +1,22 @@
+ALTER TABLE "homes"
+  ADD COLUMN "security_deposit" bigint NOT NULL DEFAULT 0,
+  ADD COLUMN "cleaning_fee" bigint NOT NULL DEFAULT 0,
+  ADD COLUMN "service_fee" bigint NOT NULL DEFAULT 0,
+  ADD COLUMN "weekly_discount" int NOT NULL DEFAULT 0,
+  ADD COLUMN "monthly_discount" int NOT NULL DEFAULT 0,
+  ADD COLUMN "tax_rate" int NOT NULL DEFAULT 0;
+
+CREATE TABLE "transaction_line_items" (
+  "id" uuid PRIMARY KEY,
+  "transaction_id" uuid NOT NULL,
+  "kind" varchar NOT NULL,
+  "description" varchar NOT NULL,
+  "quantity" int NOT NULL,
+  "unit_price" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "transaction_line_items" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id");
+
+CREATE INDEX ON "transaction_line_items" ("transaction_id");
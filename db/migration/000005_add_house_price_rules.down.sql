@@ -0,0 +1 @@
+DROP TABLE IF EXISTS house_price_rules;
@@ -0,0 +1,15 @@
+CREATE TABLE "house_price_rules" (
+  "id" uuid PRIMARY KEY,
+  "house_id" uuid NOT NULL,
+  "name" varchar NOT NULL,
+  "start_date" date,
+  "end_date" date,
+  "weekdays" varchar NOT NULL,
+  "price" bigint NOT NULL,
+  "min_stay" int NOT NULL DEFAULT 0,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "house_price_rules" ADD FOREIGN KEY ("house_id") REFERENCES "homes" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "house_price_rules" ("house_id");
@@ -0,0 +1,3 @@
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "rental_unit";
+
+DROP TABLE IF EXISTS house_rental_plans;
@@ -0,0 +1,37 @@
+CREATE TABLE "house_rental_plans" (
+  "id" uuid PRIMARY KEY,
+  "house_id" uuid NOT NULL,
+  "unit" varchar NOT NULL,
+  "price" bigint NOT NULL,
+  "min_duration" int NOT NULL DEFAULT 1,
+  "max_duration" int NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  UNIQUE ("house_id", "unit")
+);
+
+ALTER TABLE "house_rental_plans" ADD FOREIGN KEY ("house_id") REFERENCES "homes" ("id") ON DELETE CASCADE;
+
+INSERT INTO "house_rental_plans" (
+  "id",
+  "house_id",
+  "unit",
+  "price",
+  "min_duration",
+  "max_duration"
+)
+SELECT
+  md5(random()::text || "id"::text)::uuid,
+  "id",
+  "type_rent",
+  "price",
+  1,
+  CASE "type_rent" WHEN 'day' THEN 90 WHEN 'month' THEN 24 WHEN 'year' THEN 5 ELSE 1 END
+FROM "homes";
+
+ALTER TABLE "transactions" ADD COLUMN "rental_unit" varchar NOT NULL DEFAULT '';
+
+UPDATE "transactions"
+SET "rental_unit" = "homes"."type_rent"
+FROM "homes"
+WHERE "homes"."id" = "transactions"."house_id";
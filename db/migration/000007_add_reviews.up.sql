@@ -0,0 +1,23 @@
+CREATE TABLE "reviews" (
+  "id" uuid PRIMARY KEY,
+  "transaction_id" uuid UNIQUE NOT NULL,
+  "house_id" uuid NOT NULL,
+  "tenant_id" uuid NOT NULL,
+  "owner_id" uuid NOT NULL,
+  "rating" int NOT NULL CHECK ("rating" BETWEEN 1 AND 5),
+  "comment" varchar NOT NULL,
+  "owner_reply" varchar NOT NULL DEFAULT '',
+  "replied_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "reviews" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id");
+
+ALTER TABLE "reviews" ADD FOREIGN KEY ("house_id") REFERENCES "homes" ("id");
+
+ALTER TABLE "reviews" ADD FOREIGN KEY ("tenant_id") REFERENCES "users" ("id");
+
+ALTER TABLE "reviews" ADD FOREIGN KEY ("owner_id") REFERENCES "users" ("id");
+
+CREATE INDEX ON "reviews" ("house_id", "created_at");
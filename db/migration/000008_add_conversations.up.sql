@@ -0,0 +1,37 @@
+CREATE TABLE "conversations" (
+  "id" uuid PRIMARY KEY,
+  "tenant_id" uuid NOT NULL,
+  "owner_id" uuid NOT NULL,
+  "house_id" uuid,
+  "transaction_id" uuid,
+  "last_message_at" timestamp NOT NULL DEFAULT (now()),
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "messages" (
+  "id" uuid PRIMARY KEY,
+  "conversation_id" uuid NOT NULL,
+  "sender_id" uuid NOT NULL,
+  "body" varchar NOT NULL,
+  "read_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "conversations" ADD FOREIGN KEY ("tenant_id") REFERENCES "users" ("id");
+
+ALTER TABLE "conversations" ADD FOREIGN KEY ("owner_id") REFERENCES "users" ("id");
+
+ALTER TABLE "conversations" ADD FOREIGN KEY ("house_id") REFERENCES "homes" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "conversations" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id");
+
+ALTER TABLE "messages" ADD FOREIGN KEY ("conversation_id") REFERENCES "conversations" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "messages" ADD FOREIGN KEY ("sender_id") REFERENCES "users" ("id");
+
+CREATE INDEX ON "conversations" ("tenant_id", "last_message_at");
+
+CREATE INDEX ON "conversations" ("owner_id", "last_message_at");
+
+CREATE INDEX ON "messages" ("conversation_id", "created_at");
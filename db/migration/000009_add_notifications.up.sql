@@ -0,0 +1,13 @@
+CREATE TABLE "notifications" (
+  "id" uuid PRIMARY KEY,
+  "user_id" uuid NOT NULL,
+  "type" varchar NOT NULL,
+  "message" varchar NOT NULL,
+  "data" jsonb NOT NULL DEFAULT '{}',
+  "read_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "notifications" ("user_id", "created_at");
@@ -0,0 +1,35 @@
+CREATE TABLE "webhooks" (
+  "id" uuid PRIMARY KEY,
+  "user_id" uuid NOT NULL,
+  "url" varchar NOT NULL,
+  "secret" varchar NOT NULL,
+  "events" text[] NOT NULL,
+  "is_global" boolean NOT NULL DEFAULT false,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "webhook_deliveries" (
+  "id" uuid PRIMARY KEY,
+  "webhook_id" uuid NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" int NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamp NOT NULL DEFAULT (now()),
+  "last_status_code" int NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "delivered_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "webhooks" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("webhook_id") REFERENCES "webhooks" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "webhooks" ("user_id");
+
+CREATE INDEX ON "webhook_deliveries" ("status", "next_attempt_at");
+
+CREATE INDEX ON "webhook_deliveries" ("webhook_id", "created_at");
@@ -0,0 +1,10 @@
+CREATE TABLE "notification_preferences" (
+  "user_id" uuid PRIMARY KEY,
+  "language" varchar NOT NULL DEFAULT 'id',
+  "email_new_booking" boolean NOT NULL DEFAULT true,
+  "email_payment_proof" boolean NOT NULL DEFAULT true,
+  "email_booking_status" boolean NOT NULL DEFAULT true,
+  "updated_at" timestamp NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "notification_preferences" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS admin_audit_logs;
+
+ALTER TABLE "homes" DROP COLUMN IF EXISTS "moderation_note";
+
+ALTER TABLE "homes" DROP COLUMN IF EXISTS "status";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "suspension_reason";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "suspended_at";
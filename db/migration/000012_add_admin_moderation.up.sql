@@ -0,0 +1,25 @@
+ALTER TABLE "users" ADD COLUMN "suspended_at" timestamp;
+
+ALTER TABLE "users" ADD COLUMN "suspension_reason" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "homes" ADD COLUMN "status" varchar NOT NULL DEFAULT 'published';
+
+ALTER TABLE "homes" ADD COLUMN "moderation_note" varchar NOT NULL DEFAULT '';
+
+CREATE TABLE "admin_audit_logs" (
+  "id" uuid PRIMARY KEY,
+  "admin_id" uuid NOT NULL,
+  "action" varchar NOT NULL,
+  "target_type" varchar NOT NULL,
+  "target_id" uuid NOT NULL,
+  "detail" jsonb NOT NULL DEFAULT '{}',
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "admin_audit_logs" ADD FOREIGN KEY ("admin_id") REFERENCES "users" ("id");
+
+CREATE INDEX ON "admin_audit_logs" ("created_at");
+
+CREATE INDEX ON "admin_audit_logs" ("target_id");
+
+CREATE INDEX ON "homes" ("status");
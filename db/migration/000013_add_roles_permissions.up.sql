@@ -0,0 +1,63 @@
+CREATE TABLE "roles" (
+  "name" varchar PRIMARY KEY,
+  "description" varchar NOT NULL
+);
+
+CREATE TABLE "permissions" (
+  "name" varchar PRIMARY KEY,
+  "description" varchar NOT NULL
+);
+
+CREATE TABLE "role_permissions" (
+  "role_name" varchar NOT NULL,
+  "permission_name" varchar NOT NULL,
+  PRIMARY KEY ("role_name", "permission_name")
+);
+
+CREATE TABLE "user_roles" (
+  "user_id" uuid NOT NULL,
+  "role_name" varchar NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("user_id", "role_name")
+);
+
+ALTER TABLE "role_permissions" ADD FOREIGN KEY ("role_name") REFERENCES "roles" ("name") ON DELETE CASCADE;
+
+ALTER TABLE "role_permissions" ADD FOREIGN KEY ("permission_name") REFERENCES "permissions" ("name") ON DELETE CASCADE;
+
+ALTER TABLE "user_roles" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "user_roles" ADD FOREIGN KEY ("role_name") REFERENCES "roles" ("name");
+
+INSERT INTO "roles" ("name", "description") VALUES
+  ('tenant', 'Book and review houses'),
+  ('owner', 'List houses and manage their bookings'),
+  ('admin', 'Moderate users, houses and transactions');
+
+INSERT INTO "permissions" ("name", "description") VALUES
+  ('house.manage', 'Create, update and delete own houses'),
+  ('transaction.create', 'Book a house and pay for it'),
+  ('transaction.manage', 'Approve or reject bookings of own houses'),
+  ('review.create', 'Review a finished booking'),
+  ('review.reply', 'Reply to reviews of own houses'),
+  ('webhook.manage', 'Manage own webhooks'),
+  ('user.moderate', 'Search and suspend users'),
+  ('house.moderate', 'Unpublish and delete any house'),
+  ('transaction.moderate', 'View and resolve any transaction'),
+  ('audit.view', 'View the audit trail of admin actions');
+
+INSERT INTO "role_permissions" ("role_name", "permission_name") VALUES
+  ('tenant', 'transaction.create'),
+  ('tenant', 'review.create'),
+  ('owner', 'house.manage'),
+  ('owner', 'transaction.manage'),
+  ('owner', 'review.reply'),
+  ('owner', 'webhook.manage'),
+  ('admin', 'webhook.manage'),
+  ('admin', 'user.moderate'),
+  ('admin', 'house.moderate'),
+  ('admin', 'transaction.moderate'),
+  ('admin', 'audit.view');
+
+INSERT INTO "user_roles" ("user_id", "role_name")
+SELECT "id", "role" FROM "users";
@@ -0,0 +1,3 @@
+UPDATE "homes" SET "status" = 'unlisted' WHERE "status" IN ('draft', 'pending_review', 'archived');
+
+ALTER TABLE "homes" ALTER COLUMN "status" SET DEFAULT 'published';
@@ -0,0 +1 @@
+ALTER TABLE "homes" ALTER COLUMN "status" SET DEFAULT 'draft';
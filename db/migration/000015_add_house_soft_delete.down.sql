@@ -0,0 +1 @@
+ALTER TABLE "homes" DROP COLUMN IF EXISTS "deleted_at";
@@ -0,0 +1,3 @@
+ALTER TABLE "homes" ADD COLUMN "deleted_at" timestamp;
+
+CREATE INDEX ON "homes" ("owner_id", "deleted_at");
@@ -0,0 +1 @@
+ALTER TABLE "users" ADD COLUMN "contact_visibility" varchar NOT NULL DEFAULT 'tenants';
@@ -0,0 +1,12 @@
+CREATE TABLE "favorites" (
+  "user_id" uuid NOT NULL,
+  "house_id" uuid NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("user_id", "house_id")
+);
+
+CREATE INDEX ON "favorites" ("house_id");
+
+ALTER TABLE "favorites" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "favorites" ADD FOREIGN KEY ("house_id") REFERENCES "homes" ("id") ON DELETE CASCADE;
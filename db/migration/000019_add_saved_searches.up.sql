@@ -0,0 +1,29 @@
+CREATE TABLE "saved_searches" (
+  "id" uuid PRIMARY KEY,
+  "user_id" uuid NOT NULL,
+  "name" varchar NOT NULL,
+  "filter" jsonb NOT NULL DEFAULT '{}',
+  "alert_channel" varchar NOT NULL DEFAULT 'notification',
+  "alert_frequency" varchar NOT NULL DEFAULT 'instant',
+  "last_alerted_at" timestamp NOT NULL DEFAULT (now()),
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "saved_search_matches" (
+  "saved_search_id" uuid NOT NULL,
+  "house_id" uuid NOT NULL,
+  "alerted_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("saved_search_id", "house_id")
+);
+
+CREATE INDEX ON "saved_searches" ("user_id");
+
+CREATE INDEX ON "saved_searches" ("alert_frequency", "last_alerted_at");
+
+ALTER TABLE "saved_searches" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "saved_search_matches" ADD FOREIGN KEY ("saved_search_id") REFERENCES "saved_searches" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "saved_search_matches" ADD FOREIGN KEY ("house_id") REFERENCES "homes" ("id") ON DELETE CASCADE;
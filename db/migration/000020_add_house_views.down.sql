@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS transactions_owner_id_created_at_idx;
+
+DROP TABLE IF EXISTS house_views;
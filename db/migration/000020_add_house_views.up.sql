@@ -0,0 +1,10 @@
+CREATE TABLE "house_views" (
+  "house_id" uuid NOT NULL,
+  "viewed_on" date NOT NULL,
+  "view_count" bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY ("house_id", "viewed_on")
+);
+
+ALTER TABLE "house_views" ADD FOREIGN KEY ("house_id") REFERENCES "homes" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "transactions" ("owner_id", "created_at");
@@ -0,0 +1,22 @@
+CREATE TABLE "house_imports" (
+  "id" uuid PRIMARY KEY,
+  "owner_id" uuid NOT NULL,
+  "mode" varchar NOT NULL DEFAULT 'all_or_nothing',
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "rows" jsonb NOT NULL,
+  "images" bytea,
+  "total_rows" int NOT NULL,
+  "processed_rows" int NOT NULL DEFAULT 0,
+  "created_rows" int NOT NULL DEFAULT 0,
+  "errors" jsonb NOT NULL DEFAULT '[]',
+  "lease_until" timestamp NOT NULL DEFAULT (now()),
+  "finished_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "house_imports" ADD FOREIGN KEY ("owner_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "house_imports" ("owner_id", "created_at");
+
+CREATE INDEX ON "house_imports" ("status", "lease_until");
@@ -0,0 +1,18 @@
+CREATE TABLE "idempotency_keys" (
+  "user_id" uuid NOT NULL,
+  "key" varchar NOT NULL,
+  "fingerprint" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'processing',
+  "response_code" int NOT NULL DEFAULT 0,
+  "response_content_type" varchar NOT NULL DEFAULT '',
+  "response_body" bytea,
+  "locked_until" timestamp NOT NULL,
+  "expires_at" timestamp NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("user_id", "key")
+);
+
+ALTER TABLE "idempotency_keys" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "idempotency_keys" ("expires_at");
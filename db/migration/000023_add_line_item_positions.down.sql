@@ -0,0 +1 @@
+ALTER TABLE "transaction_line_items" DROP COLUMN IF EXISTS "position";
@@ -0,0 +1,25 @@
+ALTER TABLE "transaction_line_items" ADD COLUMN "position" int NOT NULL DEFAULT 0;
+
+-- the line items of a transaction are inserted at once, so the existing ones are ordered by the breakdown order of their kind
+UPDATE transaction_line_items SET position = ordered.position
+FROM (
+  SELECT
+    id,
+    ROW_NUMBER() OVER (
+      PARTITION BY transaction_id
+      ORDER BY
+        CASE kind
+          WHEN 'rent' THEN 0
+          WHEN 'discount' THEN 1
+          WHEN 'cleaning_fee' THEN 2
+          WHEN 'service_fee' THEN 3
+          WHEN 'tax' THEN 4
+          WHEN 'security_deposit' THEN 5
+          ELSE 6
+        END,
+        created_at,
+        id
+    ) - 1 AS position
+  FROM transaction_line_items
+) AS ordered
+WHERE transaction_line_items.id = ordered.id;
@@ -0,0 +1 @@
+-- the settled submissions are left as they are, as they should never be waiting for approval again
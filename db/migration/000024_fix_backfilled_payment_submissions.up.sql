@@ -0,0 +1,11 @@
+-- a submission is only waiting for approval while it's transaction is, as the status of a transaction is synced
+-- from it's latest submission & a cancelled one has it's waiting submissions rejected, the backfill of 000002 is
+-- what left a settled proof (of an approved, rejected or cancelled transaction) waiting to be approved again
+UPDATE payment_submissions
+SET
+  status = CASE WHEN transactions.payment_status = 'approved' THEN 'approved' ELSE 'rejected' END,
+  updated_at = now()
+FROM transactions
+WHERE payment_submissions.transaction_id = transactions.id
+  AND payment_submissions.status = 'waiting-approve'
+  AND transactions.payment_status <> 'waiting-approve';
@@ -0,0 +1 @@
+ALTER TABLE "house_imports" DROP COLUMN IF EXISTS "uploaded_images";
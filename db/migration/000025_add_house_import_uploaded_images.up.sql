@@ -0,0 +1,2 @@
+-- the images an all or nothing import already uploaded, so they're reused when it's picked up again
+ALTER TABLE "house_imports" ADD COLUMN "uploaded_images" jsonb NOT NULL DEFAULT '[]';
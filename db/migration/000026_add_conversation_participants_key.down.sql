@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "conversations_participants_key";
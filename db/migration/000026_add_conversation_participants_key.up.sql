@@ -0,0 +1,34 @@
+-- the conversations duplicated by concurrent requests are merged into the earliest one before they're prevented
+CREATE TEMPORARY TABLE duplicate_conversations AS
+SELECT id, kept_id FROM (
+  SELECT
+    id,
+    first_value(id) OVER (PARTITION BY tenant_id, owner_id, house_id, transaction_id ORDER BY created_at, id) AS kept_id
+  FROM conversations
+) AS ranked
+WHERE id <> kept_id;
+
+UPDATE messages SET conversation_id = duplicate_conversations.kept_id
+FROM duplicate_conversations
+WHERE messages.conversation_id = duplicate_conversations.id;
+
+UPDATE conversations SET last_message_at = merged.last_message_at
+FROM (
+  SELECT duplicate_conversations.kept_id, MAX(conversations.last_message_at) AS last_message_at
+  FROM duplicate_conversations
+  JOIN conversations ON conversations.id = duplicate_conversations.id
+  GROUP BY duplicate_conversations.kept_id
+) AS merged
+WHERE conversations.id = merged.kept_id AND conversations.last_message_at < merged.last_message_at;
+
+DELETE FROM conversations WHERE id IN (SELECT id FROM duplicate_conversations);
+
+DROP TABLE duplicate_conversations;
+
+-- a conversation without a house or a transaction is keyed by the nil uuid, as a null is never equal to another
+CREATE UNIQUE INDEX "conversations_participants_key" ON "conversations" (
+  "tenant_id",
+  "owner_id",
+  COALESCE("house_id", '00000000-0000-0000-0000-000000000000'),
+  COALESCE("transaction_id", '00000000-0000-0000-0000-000000000000')
+);
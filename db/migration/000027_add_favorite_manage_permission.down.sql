@@ -0,0 +1,3 @@
+DELETE FROM "role_permissions" WHERE "permission_name" = 'favorite.manage';
+
+DELETE FROM "permissions" WHERE "name" = 'favorite.manage';
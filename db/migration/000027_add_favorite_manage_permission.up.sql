@@ -0,0 +1,5 @@
+INSERT INTO "permissions" ("name", "description") VALUES
+  ('favorite.manage', 'Favorite houses and list them');
+
+INSERT INTO "role_permissions" ("role_name", "permission_name") VALUES
+  ('tenant', 'favorite.manage');
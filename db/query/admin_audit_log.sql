@@ -0,0 +1,11 @@
+-- name: CreateAdminAuditLog :one
+INSERT INTO admin_audit_logs (
+  id,
+  admin_id,
+  action,
+  target_type,
+  target_id,
+  detail
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
@@ -0,0 +1,77 @@
+-- name: CreateConversation :one
+INSERT INTO conversations (
+  id,
+  tenant_id,
+  owner_id,
+  house_id,
+  transaction_id
+) VALUES (
+  $1, $2, $3, $4, $5
+) ON CONFLICT DO NOTHING
+RETURNING *;
+
+-- name: UpdateConversationLastMessage :exec
+UPDATE conversations 
+SET 
+  last_message_at = $2,
+  updated_at = $2
+WHERE id = $1;
+
+-- name: GetConversationById :one
+SELECT * FROM conversations
+WHERE conversations.id = $1 LIMIT 1;
+
+-- name: GetConversationByParticipants :one
+SELECT * FROM conversations
+WHERE tenant_id = $1 
+  AND owner_id = $2 
+  AND house_id IS NOT DISTINCT FROM $3 
+  AND transaction_id IS NOT DISTINCT FROM $4
+LIMIT 1;
+
+-- name: ListConversation :many
+SELECT 
+  conversations.id,
+  conversations.house_id,
+  conversations.transaction_id,
+  conversations.last_message_at,
+  conversations.created_at,
+  counterpart.id AS counterpart_id,
+  counterpart.fullname AS counterpart_fullname,
+  counterpart.avatar AS counterpart_avatar,
+  COALESCE(homes.title, '')::varchar AS house_title,
+  COALESCE(last_message.body, '')::varchar AS last_message,
+  (
+    SELECT COUNT(*) FROM messages 
+    WHERE messages.conversation_id = conversations.id 
+      AND messages.sender_id <> $1 
+      AND messages.read_at IS NULL
+  ) AS unread_count
+FROM conversations
+JOIN users AS counterpart
+ON counterpart.id = CASE WHEN conversations.tenant_id = $1 THEN conversations.owner_id ELSE conversations.tenant_id END
+LEFT JOIN homes
+ON homes.id = conversations.house_id
+LEFT JOIN LATERAL (
+  SELECT body FROM messages 
+  WHERE messages.conversation_id = conversations.id 
+  ORDER BY messages.created_at DESC LIMIT 1
+) AS last_message ON true
+WHERE conversations.tenant_id = $1 OR conversations.owner_id = $1
+ORDER BY conversations.last_message_at DESC;
+
+-- name: GetOwnerResponseRate :one
+SELECT
+  COUNT(*) AS conversation_count,
+  COUNT(*) FILTER (
+    WHERE EXISTS (
+      SELECT 1 FROM messages
+      WHERE messages.conversation_id = conversations.id AND messages.sender_id = conversations.owner_id
+    )
+  ) AS replied_count
+FROM conversations
+WHERE owner_id = $1
+  AND EXISTS (
+    SELECT 1 FROM messages
+    WHERE messages.conversation_id = conversations.id AND messages.sender_id = conversations.tenant_id
+  );
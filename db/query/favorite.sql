@@ -0,0 +1,30 @@
+-- name: AddFavorite :exec
+INSERT INTO favorites (
+  user_id,
+  house_id
+) VALUES (
+  $1, $2
+) ON CONFLICT DO NOTHING;
+
+-- name: DeleteFavorite :exec
+DELETE FROM favorites
+WHERE user_id = $1 AND house_id = $2;
+
+-- name: DeleteFavoriteByUser :exec
+DELETE FROM favorites
+WHERE user_id = $1;
+
+-- name: IsFavoriteHouse :one
+SELECT EXISTS (
+  SELECT 1 FROM favorites
+  WHERE user_id = $1 AND house_id = $2
+);
+
+-- name: ListFavoriteHouseIdByHouseIds :many
+SELECT house_id FROM favorites
+WHERE user_id = $1 AND house_id = ANY(sqlc.arg(house_ids)::uuid[]);
+
+-- name: CountFavoriteByHouseIds :many
+SELECT house_id, COUNT(*) AS favorite_count FROM favorites
+WHERE house_id = ANY($1::uuid[])
+GROUP BY house_id;
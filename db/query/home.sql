@@ -12,32 +12,77 @@ INSERT INTO homes (
   city_id,
   description,
   amenities,
-  area
+  area,
+  security_deposit,
+  cleaning_fee,
+  service_fee,
+  weekly_discount,
+  monthly_discount,
+  tax_rate,
+  status
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20
 ) RETURNING *;
 
 -- name: UpdateHouse :one
 UPDATE homes
 SET
-  title = $2,
-  featured_image = $3,
-  bedrooms = $4,
-  bathrooms = $5,
-  type_rent = $6,
-  price = $7,
-  province_id = $8,
-  city_id = $9,
-  description = $10,
-  amenities = $11,
-  area = $12
-WHERE id = $1
+  title = sqlc.arg(title),
+  featured_image = sqlc.arg(featured_image),
+  bedrooms = sqlc.arg(bedrooms),
+  bathrooms = sqlc.arg(bathrooms),
+  type_rent = sqlc.arg(type_rent),
+  price = sqlc.arg(price),
+  province_id = sqlc.arg(province_id),
+  city_id = sqlc.arg(city_id),
+  description = sqlc.arg(description),
+  amenities = sqlc.arg(amenities),
+  area = sqlc.arg(area),
+  security_deposit = sqlc.arg(security_deposit),
+  cleaning_fee = sqlc.arg(cleaning_fee),
+  service_fee = sqlc.arg(service_fee),
+  weekly_discount = sqlc.arg(weekly_discount),
+  monthly_discount = sqlc.arg(monthly_discount),
+  tax_rate = sqlc.arg(tax_rate),
+  updated_at = sqlc.arg(updated_at)
+WHERE id = sqlc.arg(id) AND updated_at = sqlc.arg(expected_updated_at)
 RETURNING *;
 
--- name: DeleteHouse :exec
-DELETE FROM homes 
+-- name: UpdateHousePrimaryRentalPlan :execrows
+UPDATE homes
+SET
+  type_rent = sqlc.arg(type_rent),
+  price = sqlc.arg(price),
+  updated_at = sqlc.arg(updated_at)
+WHERE id = sqlc.arg(id) AND updated_at = sqlc.arg(expected_updated_at);
+
+-- name: UpdateHouseStatus :execrows
+UPDATE homes
+SET
+  status = sqlc.arg(status),
+  moderation_note = sqlc.arg(moderation_note),
+  updated_at = sqlc.arg(updated_at)
+WHERE id = sqlc.arg(id) AND updated_at = sqlc.arg(expected_updated_at);
+
+-- name: SoftDeleteHouse :exec
+UPDATE homes
+SET
+  status = 'archived',
+  deleted_at = $2,
+  updated_at = $2
 WHERE id = $1;
 
+-- name: RestoreHouse :execrows
+UPDATE homes
+SET
+  status = 'draft',
+  deleted_at = NULL,
+  updated_at = sqlc.arg(updated_at)
+WHERE id = sqlc.arg(id) AND updated_at = sqlc.arg(expected_updated_at);
+
+-- name: GetHouseDeletedAt :one
+SELECT deleted_at FROM homes WHERE id = $1 LIMIT 1;
+
 -- name: GetHouseById :one
 SELECT 
   homes.id,
@@ -52,6 +97,14 @@ SELECT
   homes.description,
   homes.amenities,
   homes.area,
+  homes.security_deposit,
+  homes.cleaning_fee,
+  homes.service_fee,
+  homes.weekly_discount,
+  homes.monthly_discount,
+  homes.tax_rate,
+  homes.status,
+  homes.moderation_note,
   homes.created_at,
   homes.updated_at,
   owner.id AS owner_id,
@@ -61,7 +114,8 @@ SELECT
   owner.role AS owner_role, 
   owner.gender AS owner_gender, 
   owner.phone_number AS owner_phone_number, 
-  owner.address AS owner_address
+  owner.address AS owner_address,
+  owner.contact_visibility AS owner_contact_visibility
 FROM homes
 JOIN users AS owner
 ON owner.id = homes.owner_id
@@ -101,10 +155,25 @@ SELECT
   amenities,
   area,
   created_at,
-  updated_at
+  updated_at,
+  status,
+  moderation_note
 FROM homes 
-WHERE owner_id = $1
+WHERE owner_id = $1 AND (deleted_at IS NOT NULL) = sqlc.arg(deleted)::bool
 ORDER BY created_at DESC;
 
 -- name: CountHouse :one
-SELECT COUNT(*) FROM homes;
\ No newline at end of file
+SELECT COUNT(*) FROM homes;
+
+-- name: SoftDeleteHouseByOwner :exec
+UPDATE homes
+SET
+  status = 'archived',
+  deleted_at = $2,
+  updated_at = $2
+WHERE owner_id = $1 AND deleted_at IS NULL;
+
+-- name: ListHouseByOwner :many
+SELECT * FROM homes
+WHERE owner_id = $1
+ORDER BY created_at;
@@ -0,0 +1,63 @@
+-- name: CreateHouseImport :one
+INSERT INTO house_imports (
+  id,
+  owner_id,
+  mode,
+  rows,
+  images,
+  total_rows
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetHouseImportById :one
+SELECT * FROM house_imports
+WHERE house_imports.id = $1 LIMIT 1;
+
+-- name: DeleteHouseImportByOwner :exec
+DELETE FROM house_imports
+WHERE owner_id = $1;
+
+-- name: ClaimDueHouseImport :many
+UPDATE house_imports 
+SET 
+  status = 'processing',
+  lease_until = sqlc.arg(lease_until)
+WHERE id IN (
+  SELECT id FROM house_imports
+  WHERE status IN ('pending', 'processing') AND lease_until <= now()
+  ORDER BY created_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+) RETURNING *;
+
+-- name: UpdateHouseImportProgress :exec
+UPDATE house_imports 
+SET 
+  processed_rows = sqlc.arg(processed_rows),
+  created_rows = sqlc.arg(created_rows),
+  errors = errors || sqlc.arg(new_errors)::jsonb,
+  lease_until = sqlc.arg(lease_until),
+  updated_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: FinishHouseImport :exec
+UPDATE house_imports 
+SET 
+  status = sqlc.arg(status),
+  processed_rows = sqlc.arg(processed_rows),
+  created_rows = sqlc.arg(created_rows),
+  errors = errors || sqlc.arg(new_errors)::jsonb,
+  images = NULL,
+  finished_at = sqlc.arg(finished_at),
+  updated_at = sqlc.arg(finished_at)
+WHERE id = sqlc.arg(id);
+
+-- name: SaveHouseImportUploadedImages :exec
+UPDATE house_imports
+SET
+  processed_rows = sqlc.arg(processed_rows),
+  uploaded_images = sqlc.arg(uploaded_images),
+  lease_until = sqlc.arg(lease_until),
+  updated_at = now()
+WHERE id = sqlc.arg(id);
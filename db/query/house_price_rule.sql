@@ -0,0 +1,26 @@
+-- name: CreateHousePriceRule :one
+INSERT INTO house_price_rules (
+  id,
+  house_id,
+  name,
+  start_date,
+  end_date,
+  weekdays,
+  price,
+  min_stay
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: DeleteHousePriceRule :exec
+DELETE FROM house_price_rules 
+WHERE id = $1;
+
+-- name: GetHousePriceRuleById :one
+SELECT * FROM house_price_rules
+WHERE house_price_rules.id = $1 LIMIT 1;
+
+-- name: ListHousePriceRule :many
+SELECT * FROM house_price_rules
+WHERE house_id = $1
+ORDER BY created_at;
@@ -0,0 +1,40 @@
+-- name: UpsertHouseRentalPlan :one
+INSERT INTO house_rental_plans (
+  id,
+  house_id,
+  unit,
+  price,
+  min_duration,
+  max_duration
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (house_id, unit) DO UPDATE
+SET
+  price = EXCLUDED.price,
+  min_duration = EXCLUDED.min_duration,
+  max_duration = EXCLUDED.max_duration,
+  updated_at = now()
+RETURNING *;
+
+-- name: DeleteHouseRentalPlan :exec
+DELETE FROM house_rental_plans 
+WHERE id = $1;
+
+-- name: GetHouseRentalPlanById :one
+SELECT * FROM house_rental_plans
+WHERE house_rental_plans.id = $1 LIMIT 1;
+
+-- name: GetHouseRentalPlanByUnit :one
+SELECT * FROM house_rental_plans
+WHERE house_id = $1 AND unit = $2 LIMIT 1;
+
+-- name: ListHouseRentalPlan :many
+SELECT * FROM house_rental_plans
+WHERE house_id = $1
+ORDER BY created_at;
+
+-- name: ListHouseRentalPlanByHouseIds :many
+SELECT * FROM house_rental_plans
+WHERE house_id = ANY($1::uuid[])
+ORDER BY created_at;
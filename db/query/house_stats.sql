@@ -0,0 +1,50 @@
+-- name: RecordHouseView :exec
+INSERT INTO house_views (
+  house_id,
+  viewed_on,
+  view_count
+) VALUES (
+  $1, $2, 1
+) ON CONFLICT (house_id, viewed_on) DO UPDATE
+SET view_count = house_views.view_count + 1;
+
+-- name: ListStatHouseByOwner :many
+SELECT id, title, status, created_at, deleted_at FROM homes
+WHERE owner_id = $1 AND (deleted_at IS NULL OR deleted_at >= sqlc.arg(start_at)) AND created_at < sqlc.arg(end_at)
+ORDER BY created_at;
+
+-- name: ListMonthlyBookingStatByOwner :many
+SELECT
+  house_id,
+  date_trunc('month', created_at)::timestamp AS month,
+  payment_status,
+  COUNT(*) AS booking_count,
+  COALESCE(SUM(total_payment), 0)::bigint AS revenue,
+  COALESCE(SUM(EXTRACT(EPOCH FROM (check_in - created_at)) / 86400), 0)::float8 AS lead_days
+FROM transactions
+WHERE owner_id = sqlc.arg(owner_id) AND created_at >= sqlc.arg(start_at) AND created_at < sqlc.arg(end_at)
+GROUP BY house_id, month, payment_status;
+
+-- name: ListMonthlyOccupancyByOwner :many
+SELECT
+  transactions.house_id,
+  months.month::timestamp AS month,
+  SUM(EXTRACT(EPOCH FROM (
+    LEAST(transactions.check_out, months.month + interval '1 month', sqlc.arg(end_at)::timestamp)
+    - GREATEST(transactions.check_in, months.month, sqlc.arg(start_at)::timestamp)
+  )) / 86400)::float8 AS occupied_days
+FROM generate_series(date_trunc('month', sqlc.arg(start_at)::timestamp), sqlc.arg(end_at)::timestamp, interval '1 month') AS months(month)
+JOIN transactions ON transactions.check_in < LEAST(months.month + interval '1 month', sqlc.arg(end_at)::timestamp)
+  AND transactions.check_out > GREATEST(months.month, sqlc.arg(start_at)::timestamp)
+WHERE transactions.owner_id = sqlc.arg(owner_id) AND transactions.payment_status = 'approved'
+GROUP BY transactions.house_id, months.month;
+
+-- name: ListMonthlyHouseViewByOwner :many
+SELECT
+  house_views.house_id,
+  date_trunc('month', house_views.viewed_on)::timestamp AS month,
+  SUM(house_views.view_count)::bigint AS view_count
+FROM house_views
+JOIN homes ON homes.id = house_views.house_id
+WHERE homes.owner_id = sqlc.arg(owner_id) AND house_views.viewed_on >= sqlc.arg(start_at) AND house_views.viewed_on < sqlc.arg(end_at)
+GROUP BY house_views.house_id, month;
@@ -0,0 +1,51 @@
+-- name: ClaimIdempotencyKey :one
+INSERT INTO idempotency_keys (
+  user_id,
+  key,
+  fingerprint,
+  locked_until,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) ON CONFLICT (user_id, key) DO UPDATE
+SET 
+  fingerprint = EXCLUDED.fingerprint,
+  status = 'processing',
+  response_code = 0,
+  response_content_type = '',
+  response_body = NULL,
+  locked_until = EXCLUDED.locked_until,
+  expires_at = EXCLUDED.expires_at,
+  created_at = now(),
+  updated_at = now()
+WHERE idempotency_keys.expires_at <= now() OR (
+  idempotency_keys.status = 'processing' AND 
+  idempotency_keys.locked_until <= now() AND 
+  idempotency_keys.fingerprint = EXCLUDED.fingerprint
+) RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE user_id = $1 AND key = $2 LIMIT 1;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys 
+SET 
+  status = 'completed',
+  response_code = $3,
+  response_content_type = $4,
+  response_body = $5,
+  updated_at = now()
+WHERE user_id = $1 AND key = $2;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE user_id = $1 AND key = $2;
+
+-- name: DeleteExpiredIdempotencyKey :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at <= now();
+
+-- name: DeleteIdempotencyKeyByUser :exec
+DELETE FROM idempotency_keys
+WHERE user_id = $1;
@@ -0,0 +1,39 @@
+-- name: CreateMessage :one
+INSERT INTO messages (
+  id,
+  conversation_id,
+  sender_id,
+  body,
+  created_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListConversationMessage :many
+SELECT * FROM messages
+WHERE conversation_id = $1
+ORDER BY created_at DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: MarkConversationMessageRead :execrows
+UPDATE messages 
+SET read_at = $3
+WHERE conversation_id = $1 
+  AND sender_id <> $2 
+  AND read_at IS NULL;
+
+-- name: CountUnreadMessage :one
+SELECT COUNT(*) FROM messages
+JOIN conversations
+ON conversations.id = messages.conversation_id
+WHERE (conversations.tenant_id = $1 OR conversations.owner_id = $1)
+  AND messages.sender_id <> $1
+  AND messages.read_at IS NULL;
+
+-- name: ListMessageByUser :many
+SELECT messages.id, messages.conversation_id, messages.sender_id, messages.body, messages.read_at, messages.created_at FROM messages
+JOIN conversations
+ON conversations.id = messages.conversation_id
+WHERE conversations.tenant_id = $1 OR conversations.owner_id = $1
+ORDER BY messages.created_at;
@@ -0,0 +1,36 @@
+-- name: CreateNotification :one
+INSERT INTO notifications (
+  id,
+  user_id,
+  type,
+  message,
+  data,
+  created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: ListNotification :many
+SELECT * FROM notifications
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: CountUnreadNotification :one
+SELECT COUNT(*) FROM notifications
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications 
+SET read_at = COALESCE(read_at, $3)
+WHERE id = $1 AND user_id = $2;
+
+-- name: MarkAllNotificationRead :exec
+UPDATE notifications 
+SET read_at = $2
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: DeleteNotificationByUser :exec
+DELETE FROM notifications
+WHERE user_id = $1;
@@ -0,0 +1,26 @@
+-- name: UpsertNotificationPreference :one
+INSERT INTO notification_preferences (
+  user_id,
+  language,
+  email_new_booking,
+  email_payment_proof,
+  email_booking_status,
+  updated_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) ON CONFLICT (user_id) DO UPDATE
+SET
+  language = EXCLUDED.language,
+  email_new_booking = EXCLUDED.email_new_booking,
+  email_payment_proof = EXCLUDED.email_payment_proof,
+  email_booking_status = EXCLUDED.email_booking_status,
+  updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: GetNotificationPreference :one
+SELECT * FROM notification_preferences
+WHERE user_id = $1 LIMIT 1;
+
+-- name: DeleteNotificationPreference :exec
+DELETE FROM notification_preferences
+WHERE user_id = $1;
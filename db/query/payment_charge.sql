@@ -0,0 +1,30 @@
+-- name: CreatePaymentCharge :one
+INSERT INTO payment_charges (
+  id,
+  transaction_id,
+  provider,
+  provider_ref,
+  amount,
+  status,
+  redirect_url
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: UpdatePendingPaymentChargeStatus :execrows
+UPDATE payment_charges 
+SET 
+  status = $2,
+  updated_at = $3
+WHERE id = $1 AND status = 'pending';
+
+-- name: GetPaymentChargeById :one
+SELECT * FROM payment_charges
+WHERE payment_charges.id = $1 LIMIT 1;
+
+-- name: UpdatePaymentChargeStatus :exec
+UPDATE payment_charges
+SET
+  status = $2,
+  updated_at = $3
+WHERE id = $1;
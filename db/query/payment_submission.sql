@@ -0,0 +1,61 @@
+-- name: CreatePaymentSubmission :one
+INSERT INTO payment_submissions (
+  id,
+  transaction_id,
+  payment_proof,
+  amount,
+  transfer_date,
+  payment_method,
+  notes,
+  status
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: UpdatePaymentSubmissionStatusById :exec
+UPDATE payment_submissions 
+SET 
+  status = $2,
+  rejection_reason = $3,
+  updated_at = $4
+WHERE id = $1;
+
+-- name: GetPaymentSubmissionById :one
+SELECT * FROM payment_submissions
+WHERE payment_submissions.id = $1 LIMIT 1;
+
+-- name: GetLatestPaymentSubmission :one
+SELECT * FROM payment_submissions
+WHERE transaction_id = $1
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: ListPaymentSubmission :many
+SELECT * FROM payment_submissions
+WHERE transaction_id = $1
+ORDER BY created_at DESC;
+
+-- name: RejectWaitingPaymentSubmission :exec
+UPDATE payment_submissions
+SET
+  status = 'rejected',
+  rejection_reason = $2,
+  updated_at = $3
+WHERE transaction_id = $1 AND status = 'waiting-approve';
+
+-- name: ListLegacyPaymentProof :many
+SELECT payment_proof FROM payment_submissions
+WHERE payment_proof <> '' AND payment_proof NOT LIKE '%/authenticated/%'
+UNION
+SELECT payment_proof FROM transactions
+WHERE payment_proof <> '' AND payment_proof NOT LIKE '%/authenticated/%';
+
+-- name: ReplacePaymentProof :exec
+WITH replaced_submissions AS (
+  UPDATE payment_submissions
+  SET payment_proof = sqlc.arg(new_payment_proof)
+  WHERE payment_proof = sqlc.arg(old_payment_proof)
+)
+UPDATE transactions
+SET payment_proof = sqlc.arg(new_payment_proof)
+WHERE payment_proof = sqlc.arg(old_payment_proof);
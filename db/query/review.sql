@@ -0,0 +1,58 @@
+-- name: CreateReview :one
+INSERT INTO reviews (
+  id,
+  transaction_id,
+  house_id,
+  tenant_id,
+  owner_id,
+  rating,
+  comment
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: UpdateReviewReply :execrows
+UPDATE reviews 
+SET 
+  owner_reply = $2,
+  replied_at = $3,
+  updated_at = $3
+WHERE id = $1 AND owner_reply = '';
+
+-- name: GetReviewById :one
+SELECT * FROM reviews
+WHERE reviews.id = $1 LIMIT 1;
+
+-- name: GetReviewByTransactionId :one
+SELECT * FROM reviews
+WHERE transaction_id = $1 LIMIT 1;
+
+-- name: ListHouseReview :many
+SELECT 
+  reviews.id,
+  reviews.rating,
+  reviews.comment,
+  reviews.owner_reply,
+  reviews.replied_at,
+  reviews.created_at,
+  tenant.fullname AS tenant_fullname,
+  tenant.avatar AS tenant_avatar
+FROM reviews
+JOIN users AS tenant
+ON tenant.id = reviews.tenant_id
+WHERE reviews.house_id = $1
+ORDER BY reviews.created_at DESC;
+
+-- name: GetHouseRating :one
+SELECT 
+  COALESCE(AVG(rating), 0)::float8 AS rating_average,
+  COUNT(*) AS rating_count
+FROM reviews
+WHERE house_id = $1;
+
+-- name: GetOwnerRating :one
+SELECT 
+  COALESCE(AVG(rating), 0)::float8 AS rating_average,
+  COUNT(*) AS rating_count
+FROM reviews
+WHERE owner_id = $1;
@@ -0,0 +1,39 @@
+-- name: AddUserRole :exec
+INSERT INTO user_roles (
+  user_id,
+  role_name
+) VALUES (
+  $1, $2
+) ON CONFLICT DO NOTHING;
+
+-- name: ListUserRole :many
+SELECT role_name FROM user_roles
+WHERE user_id = $1
+ORDER BY role_name;
+
+-- name: HasUserRole :one
+SELECT EXISTS (
+  SELECT 1 FROM user_roles
+  WHERE user_id = $1 AND role_name = $2
+);
+
+-- name: GetUserAccessById :one
+SELECT
+  users.role,
+  users.suspended_at,
+  ARRAY(
+    SELECT user_roles.role_name FROM user_roles
+    WHERE user_roles.user_id = users.id
+    ORDER BY user_roles.role_name
+  )::text[] AS roles,
+  ARRAY(
+    SELECT DISTINCT role_permissions.permission_name FROM user_roles
+    JOIN role_permissions ON role_permissions.role_name = user_roles.role_name
+    WHERE user_roles.user_id = users.id
+  )::text[] AS permissions
+FROM users
+WHERE users.id = $1 AND users.deleted_at IS NULL LIMIT 1;
+
+-- name: DeleteUserRoleByUser :exec
+DELETE FROM user_roles
+WHERE user_id = $1;
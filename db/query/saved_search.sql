@@ -0,0 +1,92 @@
+-- name: CreateSavedSearch :one
+INSERT INTO saved_searches (
+  id,
+  user_id,
+  name,
+  filter,
+  alert_channel,
+  alert_frequency
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetSavedSearchById :one
+SELECT * FROM saved_searches
+WHERE id = $1 LIMIT 1;
+
+-- name: ListSavedSearchByUser :many
+SELECT * FROM saved_searches
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: DeleteSavedSearch :exec
+DELETE FROM saved_searches
+WHERE id = $1;
+
+-- name: DeleteSavedSearchByUser :exec
+DELETE FROM saved_searches
+WHERE user_id = $1;
+
+-- name: MatchSavedSearch :many
+WITH matched AS (
+  INSERT INTO saved_search_matches (
+    saved_search_id,
+    house_id
+  )
+  SELECT saved_searches.id, homes.id FROM saved_searches
+  JOIN users ON users.id = saved_searches.user_id
+  JOIN homes ON homes.id = sqlc.arg(house_id) AND homes.owner_id <> saved_searches.user_id
+  LEFT JOIN (
+    SELECT house_id, AVG(rating)::float8 AS rating_average FROM reviews
+    WHERE house_id = sqlc.arg(house_id)
+    GROUP BY house_id
+  ) AS rating ON rating.house_id = homes.id
+  WHERE users.deleted_at IS NULL AND users.suspended_at IS NULL AND homes.status = 'published'
+    AND homes.bedrooms >= COALESCE((saved_searches.filter->>'bedrooms')::int, 0)
+    AND homes.bathrooms >= COALESCE((saved_searches.filter->>'bathrooms')::int, 0)
+    AND COALESCE((saved_searches.filter->>'province_id')::int, homes.province_id) = homes.province_id
+    AND COALESCE((saved_searches.filter->>'city_id')::int, homes.city_id) = homes.city_id
+    AND COALESCE(rating.rating_average, 0) >= COALESCE((saved_searches.filter->>'rating')::float8, 0)
+    AND (
+      (saved_searches.filter->>'type_rent' IS NULL AND saved_searches.filter->>'price' IS NULL) OR EXISTS (
+        SELECT 1 FROM house_rental_plans AS plan
+        WHERE plan.house_id = homes.id
+          AND plan.unit = COALESCE(saved_searches.filter->>'type_rent', plan.unit)
+          AND plan.price <= COALESCE((saved_searches.filter->>'price')::bigint, plan.price)
+      )
+    )
+    AND NOT EXISTS (
+      SELECT 1 FROM unnest(string_to_array(saved_searches.filter->>'amenities', ',')) AS amenity
+      WHERE homes.amenities NOT ILIKE '%' || amenity || '%'
+    )
+  ON CONFLICT (saved_search_id, house_id) DO NOTHING
+  RETURNING saved_search_id
+)
+SELECT saved_searches.* FROM saved_searches
+JOIN matched ON matched.saved_search_id = saved_searches.id;
+
+-- name: ClaimUnalertedSavedSearchMatch :many
+WITH claimed AS (
+  UPDATE saved_search_matches
+  SET alerted_at = sqlc.arg(alerted_at)
+  FROM homes
+  WHERE homes.id = saved_search_matches.house_id AND saved_search_matches.saved_search_id = sqlc.arg(saved_search_id)
+    AND saved_search_matches.alerted_at IS NULL AND homes.status = 'published'
+  RETURNING homes.id, homes.title, homes.price, saved_search_matches.created_at
+)
+SELECT id, title, price FROM claimed
+ORDER BY created_at;
+
+-- name: ClaimDueSavedSearchDigest :many
+UPDATE saved_searches 
+SET last_alerted_at = sqlc.arg(alerted_at)
+WHERE id IN (
+  SELECT id FROM saved_searches
+  WHERE alert_frequency = 'daily' AND last_alerted_at <= sqlc.arg(due_before) AND EXISTS (
+    SELECT 1 FROM saved_search_matches
+    WHERE saved_search_matches.saved_search_id = saved_searches.id AND saved_search_matches.alerted_at IS NULL
+  )
+  ORDER BY last_alerted_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+) RETURNING *;
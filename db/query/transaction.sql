@@ -9,9 +9,10 @@ INSERT INTO transactions (
   total_payment,
   check_in,
   check_out,
-  time_rent
+  time_rent,
+  rental_unit
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 ) RETURNING *;
 
 -- name: UpdateTransactionStatusById :exec
@@ -31,4 +32,92 @@ WHERE id = $1;
 
 -- name: DeleteTransaction :exec
 DELETE FROM transactions 
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: GetTransactionById :one
+SELECT 
+  id,
+  tenant_id,
+  owner_id,
+  house_id,
+  payment_status,
+  payment_proof,
+  total_payment,
+  check_in,
+  check_out,
+  time_rent,
+  created_at,
+  updated_at,
+  rental_unit
+FROM transactions
+WHERE transactions.id = $1 LIMIT 1;
+
+-- name: CreateTransactionLineItem :one
+INSERT INTO transaction_line_items (
+  id,
+  transaction_id,
+  kind,
+  description,
+  quantity,
+  unit_price,
+  amount,
+  position
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: ExpireTransactionById :execrows
+UPDATE transactions
+SET
+  payment_status = 'cancel',
+  updated_at = $2
+WHERE id = $1 AND payment_status = 'waiting-payment';
+
+-- name: ExpireStaleTransaction :many
+UPDATE transactions
+SET
+  payment_status = 'cancel',
+  updated_at = sqlc.arg(updated_at)
+WHERE id IN (
+  SELECT id FROM transactions
+  WHERE payment_status = 'waiting-payment' AND created_at < sqlc.arg(created_before)
+  ORDER BY created_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: ListTransactionLineItem :many
+SELECT * FROM transaction_line_items
+WHERE transaction_id = $1
+ORDER BY position;
+
+-- name: CountUpcomingHouseTransaction :one
+SELECT COUNT(*) FROM transactions
+WHERE house_id = $1
+  AND payment_status IN ('waiting-payment', 'waiting-approve', 'approved')
+  AND check_out > $2;
+
+-- name: HasTenantBookedHouse :one
+SELECT EXISTS (
+  SELECT 1 FROM transactions
+  WHERE house_id = $1 AND tenant_id = $2
+);
+
+-- name: CountUpcomingUserTransaction :one
+SELECT COUNT(*) FROM transactions
+WHERE (tenant_id = $1 OR owner_id = $1)
+  AND payment_status IN ('waiting-payment', 'waiting-approve', 'approved')
+  AND check_out > $2;
+
+-- name: ListTransactionByUser :many
+SELECT * FROM transactions
+WHERE tenant_id = $1 OR owner_id = $1
+ORDER BY created_at;
+
+-- name: UpdateOpenTransactionStatus :execrows
+UPDATE transactions
+SET
+  payment_status = $2,
+  updated_at = $3
+WHERE id = $1 AND payment_status IN ('waiting-payment', 'waiting-approve', 'rejected');
@@ -14,16 +14,17 @@ INSERT INTO users (
   $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 ) RETURNING id;
 
--- name: UpdateUserById :exec
+-- name: UpdateUserById :one
 UPDATE users
 SET
-  fullname = $2,
-  email = $3,
-  gender = $4,
-  phone_number = $5,
-  address = $6,
-  updated_at = $7
-WHERE id = $1;
+  fullname = sqlc.arg(fullname),
+  email = sqlc.arg(email),
+  gender = sqlc.arg(gender),
+  phone_number = sqlc.arg(phone_number),
+  address = sqlc.arg(address),
+  updated_at = sqlc.arg(updated_at)
+WHERE id = sqlc.arg(id) AND updated_at = sqlc.arg(expected_updated_at)
+RETURNING updated_at;
 
 -- name: UpdateUserAvatarById :exec
 UPDATE users 
@@ -56,7 +57,11 @@ SELECT
   address, 
   avatar,
   created_at, 
-  updated_at
+  updated_at,
+  suspended_at,
+  suspension_reason,
+  deleted_at,
+  contact_visibility
 FROM users
 WHERE users.username = $1 LIMIT 1;
 
@@ -72,9 +77,40 @@ SELECT
   address, 
   avatar,
   created_at, 
-  updated_at
+  updated_at,
+  contact_visibility
 FROM users
 WHERE users.id = $1 LIMIT 1;
 
+-- name: UpdateUserSuspensionById :exec
+UPDATE users 
+SET 
+  suspended_at = $2,
+  suspension_reason = $3,
+  updated_at = $4
+WHERE id = $1;
+
 -- name: GetUserAvatarById :one
-SELECT avatar FROM users WHERE users.id = $1 LIMIT 1;
\ No newline at end of file
+SELECT avatar FROM users WHERE users.id = $1 LIMIT 1;
+
+-- name: AnonymizeUserById :exec
+UPDATE users 
+SET 
+  fullname = 'Deleted user',
+  username = $2,
+  email = '',
+  gender = '',
+  phone_number = '',
+  password = '',
+  address = '',
+  avatar = '',
+  deleted_at = $3,
+  updated_at = $3
+WHERE id = $1;
+
+-- name: UpdateUserContactVisibilityById :exec
+UPDATE users 
+SET 
+  contact_visibility = $2,
+  updated_at = $3
+WHERE id = $1;
@@ -0,0 +1,33 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (
+  id,
+  user_id,
+  url,
+  secret,
+  events,
+  is_global
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: DeleteWebhook :exec
+DELETE FROM webhooks 
+WHERE id = $1;
+
+-- name: GetWebhookById :one
+SELECT * FROM webhooks
+WHERE webhooks.id = $1 LIMIT 1;
+
+-- name: ListWebhookByUser :many
+SELECT * FROM webhooks
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListWebhookByEvent :many
+SELECT * FROM webhooks
+WHERE sqlc.arg(event_type)::text = ANY(events)
+  AND (user_id = sqlc.arg(owner_id) OR is_global);
+
+-- name: DeleteWebhookByUser :exec
+DELETE FROM webhooks
+WHERE user_id = $1;
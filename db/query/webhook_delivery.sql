@@ -0,0 +1,52 @@
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (
+  id,
+  webhook_id,
+  event_type,
+  payload
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ClaimDueWebhookDelivery :many
+UPDATE webhook_deliveries 
+SET next_attempt_at = sqlc.arg(lease_until)
+WHERE id IN (
+  SELECT id FROM webhook_deliveries
+  WHERE status = 'pending' AND next_attempt_at <= now()
+  ORDER BY next_attempt_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+) RETURNING *;
+
+-- name: UpdateWebhookDeliveryAttempt :exec
+UPDATE webhook_deliveries 
+SET 
+  status = $2,
+  attempts = $3,
+  next_attempt_at = $4,
+  last_status_code = $5,
+  last_error = $6,
+  delivered_at = $7,
+  updated_at = $8
+WHERE id = $1;
+
+-- name: RedeliverWebhookDelivery :exec
+UPDATE webhook_deliveries 
+SET 
+  status = 'pending',
+  attempts = 0,
+  next_attempt_at = $2,
+  updated_at = $2
+WHERE id = $1;
+
+-- name: GetWebhookDeliveryById :one
+SELECT * FROM webhook_deliveries
+WHERE webhook_deliveries.id = $1 LIMIT 1;
+
+-- name: ListWebhookDelivery :many
+SELECT * FROM webhook_deliveries
+WHERE webhook_id = $1
+ORDER BY created_at DESC
+LIMIT $2
+OFFSET $3;
@@ -5,6 +5,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
@@ -35,26 +36,40 @@ INSERT INTO homes (
   city_id,
   description,
   amenities,
-  area
+  area,
+  security_deposit,
+  cleaning_fee,
+  service_fee,
+  weekly_discount,
+  monthly_discount,
+  tax_rate,
+  status
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
-) RETURNING id, owner_id, title, featured_image, bedrooms, bathrooms, type_rent, price, province_id, city_id, description, amenities, area, created_at, updated_at
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20
+) RETURNING id, owner_id, title, featured_image, bedrooms, bathrooms, type_rent, price, province_id, city_id, description, amenities, area, created_at, updated_at, security_deposit, cleaning_fee, service_fee, weekly_discount, monthly_discount, tax_rate, status, moderation_note, deleted_at
 `
 
 type CreateHouseParams struct {
-	ID            uuid.UUID `json:"id"`
-	OwnerID       uuid.UUID `json:"owner_id"`
-	Title         string    `json:"title"`
-	FeaturedImage string    `json:"featured_image"`
-	Bedrooms      int32     `json:"bedrooms"`
-	Bathrooms     int32     `json:"bathrooms"`
-	TypeRent      string    `json:"type_rent"`
-	Price         int64     `json:"price"`
-	ProvinceID    int32     `json:"province_id"`
-	CityID        int32     `json:"city_id"`
-	Description   string    `json:"description"`
-	Amenities     string    `json:"amenities"`
-	Area          int32     `json:"area"`
+	ID              uuid.UUID `json:"id"`
+	OwnerID         uuid.UUID `json:"owner_id"`
+	Title           string    `json:"title"`
+	FeaturedImage   string    `json:"featured_image"`
+	Bedrooms        int32     `json:"bedrooms"`
+	Bathrooms       int32     `json:"bathrooms"`
+	TypeRent        string    `json:"type_rent"`
+	Price           int64     `json:"price"`
+	ProvinceID      int32     `json:"province_id"`
+	CityID          int32     `json:"city_id"`
+	Description     string    `json:"description"`
+	Amenities       string    `json:"amenities"`
+	Area            int32     `json:"area"`
+	SecurityDeposit int64     `json:"security_deposit"`
+	CleaningFee     int64     `json:"cleaning_fee"`
+	ServiceFee      int64     `json:"service_fee"`
+	WeeklyDiscount  int32     `json:"weekly_discount"`
+	MonthlyDiscount int32     `json:"monthly_discount"`
+	TaxRate         int32     `json:"tax_rate"`
+	Status          string    `json:"status"`
 }
 
 func (q *Queries) CreateHouse(ctx context.Context, arg CreateHouseParams) (Home, error) {
@@ -72,6 +87,13 @@ func (q *Queries) CreateHouse(ctx context.Context, arg CreateHouseParams) (Home,
 		arg.Description,
 		arg.Amenities,
 		arg.Area,
+		arg.SecurityDeposit,
+		arg.CleaningFee,
+		arg.ServiceFee,
+		arg.WeeklyDiscount,
+		arg.MonthlyDiscount,
+		arg.TaxRate,
+		arg.Status,
 	)
 	var i Home
 	err := row.Scan(
@@ -90,18 +112,28 @@ func (q *Queries) CreateHouse(ctx context.Context, arg CreateHouseParams) (Home,
 		&i.Area,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SecurityDeposit,
+		&i.CleaningFee,
+		&i.ServiceFee,
+		&i.WeeklyDiscount,
+		&i.MonthlyDiscount,
+		&i.TaxRate,
+		&i.Status,
+		&i.ModerationNote,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const deleteHouse = `-- name: DeleteHouse :exec
-DELETE FROM homes 
-WHERE id = $1
+const getHouseDeletedAt = `-- name: GetHouseDeletedAt :one
+SELECT deleted_at FROM homes WHERE id = $1 LIMIT 1
 `
 
-func (q *Queries) DeleteHouse(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteHouse, id)
-	return err
+func (q *Queries) GetHouseDeletedAt(ctx context.Context, id uuid.UUID) (sql.NullTime, error) {
+	row := q.db.QueryRowContext(ctx, getHouseDeletedAt, id)
+	var deleted_at sql.NullTime
+	err := row.Scan(&deleted_at)
+	return deleted_at, err
 }
 
 const getHouseById = `-- name: GetHouseById :one
@@ -118,6 +150,14 @@ SELECT
   homes.description,
   homes.amenities,
   homes.area,
+  homes.security_deposit,
+  homes.cleaning_fee,
+  homes.service_fee,
+  homes.weekly_discount,
+  homes.monthly_discount,
+  homes.tax_rate,
+  homes.status,
+  homes.moderation_note,
   homes.created_at,
   homes.updated_at,
   owner.id AS owner_id,
@@ -127,7 +167,8 @@ SELECT
   owner.role AS owner_role, 
   owner.gender AS owner_gender, 
   owner.phone_number AS owner_phone_number, 
-  owner.address AS owner_address
+  owner.address AS owner_address,
+  owner.contact_visibility AS owner_contact_visibility
 FROM homes
 JOIN users AS owner
 ON owner.id = homes.owner_id
@@ -135,28 +176,37 @@ WHERE homes.id = $1 LIMIT 1
 `
 
 type GetHouseByIdRow struct {
-	ID               uuid.UUID `json:"id"`
-	Title            string    `json:"title"`
-	FeaturedImage    string    `json:"featured_image"`
-	Bedrooms         int32     `json:"bedrooms"`
-	Bathrooms        int32     `json:"bathrooms"`
-	TypeRent         string    `json:"type_rent"`
-	Price            int64     `json:"price"`
-	ProvinceID       int32     `json:"province_id"`
-	CityID           int32     `json:"city_id"`
-	Description      string    `json:"description"`
-	Amenities        string    `json:"amenities"`
-	Area             int32     `json:"area"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
-	OwnerID          uuid.UUID `json:"owner_id"`
-	OwnerFullname    string    `json:"owner_fullname"`
-	OwnerUsername    string    `json:"owner_username"`
-	OwnerEmail       string    `json:"owner_email"`
-	OwnerRole        string    `json:"owner_role"`
-	OwnerGender      string    `json:"owner_gender"`
-	OwnerPhoneNumber string    `json:"owner_phone_number"`
-	OwnerAddress     string    `json:"owner_address"`
+	ID                     uuid.UUID `json:"id"`
+	Title                  string    `json:"title"`
+	FeaturedImage          string    `json:"featured_image"`
+	Bedrooms               int32     `json:"bedrooms"`
+	Bathrooms              int32     `json:"bathrooms"`
+	TypeRent               string    `json:"type_rent"`
+	Price                  int64     `json:"price"`
+	ProvinceID             int32     `json:"province_id"`
+	CityID                 int32     `json:"city_id"`
+	Description            string    `json:"description"`
+	Amenities              string    `json:"amenities"`
+	Area                   int32     `json:"area"`
+	SecurityDeposit        int64     `json:"security_deposit"`
+	CleaningFee            int64     `json:"cleaning_fee"`
+	ServiceFee             int64     `json:"service_fee"`
+	WeeklyDiscount         int32     `json:"weekly_discount"`
+	MonthlyDiscount        int32     `json:"monthly_discount"`
+	TaxRate                int32     `json:"tax_rate"`
+	Status                 string    `json:"status"`
+	ModerationNote         string    `json:"moderation_note"`
+	CreatedAt              time.Time `json:"created_at"`
+	UpdatedAt              time.Time `json:"updated_at"`
+	OwnerID                uuid.UUID `json:"owner_id"`
+	OwnerFullname          string    `json:"owner_fullname"`
+	OwnerUsername          string    `json:"owner_username"`
+	OwnerEmail             string    `json:"owner_email"`
+	OwnerRole              string    `json:"owner_role"`
+	OwnerGender            string    `json:"owner_gender"`
+	OwnerPhoneNumber       string    `json:"owner_phone_number"`
+	OwnerAddress           string    `json:"owner_address"`
+	OwnerContactVisibility string    `json:"owner_contact_visibility"`
 }
 
 func (q *Queries) GetHouseById(ctx context.Context, id uuid.UUID) (GetHouseByIdRow, error) {
@@ -175,6 +225,14 @@ func (q *Queries) GetHouseById(ctx context.Context, id uuid.UUID) (GetHouseByIdR
 		&i.Description,
 		&i.Amenities,
 		&i.Area,
+		&i.SecurityDeposit,
+		&i.CleaningFee,
+		&i.ServiceFee,
+		&i.WeeklyDiscount,
+		&i.MonthlyDiscount,
+		&i.TaxRate,
+		&i.Status,
+		&i.ModerationNote,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.OwnerID,
@@ -185,6 +243,7 @@ func (q *Queries) GetHouseById(ctx context.Context, id uuid.UUID) (GetHouseByIdR
 		&i.OwnerGender,
 		&i.OwnerPhoneNumber,
 		&i.OwnerAddress,
+		&i.OwnerContactVisibility,
 	)
 	return i, err
 }
@@ -264,6 +323,60 @@ func (q *Queries) ListHouse(ctx context.Context) ([]ListHouseRow, error) {
 	return items, nil
 }
 
+const listHouseByOwner = `-- name: ListHouseByOwner :many
+SELECT id, owner_id, title, featured_image, bedrooms, bathrooms, type_rent, price, province_id, city_id, description, amenities, area, created_at, updated_at, security_deposit, cleaning_fee, service_fee, weekly_discount, monthly_discount, tax_rate, status, moderation_note, deleted_at FROM homes
+WHERE owner_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListHouseByOwner(ctx context.Context, ownerID uuid.UUID) ([]Home, error) {
+	rows, err := q.db.QueryContext(ctx, listHouseByOwner, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Home
+	for rows.Next() {
+		var i Home
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerID,
+			&i.Title,
+			&i.FeaturedImage,
+			&i.Bedrooms,
+			&i.Bathrooms,
+			&i.TypeRent,
+			&i.Price,
+			&i.ProvinceID,
+			&i.CityID,
+			&i.Description,
+			&i.Amenities,
+			&i.Area,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SecurityDeposit,
+			&i.CleaningFee,
+			&i.ServiceFee,
+			&i.WeeklyDiscount,
+			&i.MonthlyDiscount,
+			&i.TaxRate,
+			&i.Status,
+			&i.ModerationNote,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listMyHouse = `-- name: ListMyHouse :many
 SELECT 
   id,
@@ -279,31 +392,40 @@ SELECT
   amenities,
   area,
   created_at,
-  updated_at
+  updated_at,
+  status,
+  moderation_note
 FROM homes 
-WHERE owner_id = $1
+WHERE owner_id = $1 AND (deleted_at IS NOT NULL) = $2::bool
 ORDER BY created_at DESC
 `
 
+type ListMyHouseParams struct {
+	OwnerID uuid.UUID `json:"owner_id"`
+	Deleted bool      `json:"deleted"`
+}
+
 type ListMyHouseRow struct {
-	ID            uuid.UUID `json:"id"`
-	Title         string    `json:"title"`
-	FeaturedImage string    `json:"featured_image"`
-	Bedrooms      int32     `json:"bedrooms"`
-	Bathrooms     int32     `json:"bathrooms"`
-	TypeRent      string    `json:"type_rent"`
-	Price         int64     `json:"price"`
-	ProvinceID    int32     `json:"province_id"`
-	CityID        int32     `json:"city_id"`
-	Description   string    `json:"description"`
-	Amenities     string    `json:"amenities"`
-	Area          int32     `json:"area"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID             uuid.UUID `json:"id"`
+	Title          string    `json:"title"`
+	FeaturedImage  string    `json:"featured_image"`
+	Bedrooms       int32     `json:"bedrooms"`
+	Bathrooms      int32     `json:"bathrooms"`
+	TypeRent       string    `json:"type_rent"`
+	Price          int64     `json:"price"`
+	ProvinceID     int32     `json:"province_id"`
+	CityID         int32     `json:"city_id"`
+	Description    string    `json:"description"`
+	Amenities      string    `json:"amenities"`
+	Area           int32     `json:"area"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Status         string    `json:"status"`
+	ModerationNote string    `json:"moderation_note"`
 }
 
-func (q *Queries) ListMyHouse(ctx context.Context, ownerID uuid.UUID) ([]ListMyHouseRow, error) {
-	rows, err := q.db.QueryContext(ctx, listMyHouse, ownerID)
+func (q *Queries) ListMyHouse(ctx context.Context, arg ListMyHouseParams) ([]ListMyHouseRow, error) {
+	rows, err := q.db.QueryContext(ctx, listMyHouse, arg.OwnerID, arg.Deleted)
 	if err != nil {
 		return nil, err
 	}
@@ -326,6 +448,8 @@ func (q *Queries) ListMyHouse(ctx context.Context, ownerID uuid.UUID) ([]ListMyH
 			&i.Area,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Status,
+			&i.ModerationNote,
 		); err != nil {
 			return nil, err
 		}
@@ -343,39 +467,53 @@ func (q *Queries) ListMyHouse(ctx context.Context, ownerID uuid.UUID) ([]ListMyH
 const updateHouse = `-- name: UpdateHouse :one
 UPDATE homes
 SET
-  title = $2,
-  featured_image = $3,
-  bedrooms = $4,
-  bathrooms = $5,
-  type_rent = $6,
-  price = $7,
-  province_id = $8,
-  city_id = $9,
-  description = $10,
-  amenities = $11,
-  area = $12
-WHERE id = $1
-RETURNING id, owner_id, title, featured_image, bedrooms, bathrooms, type_rent, price, province_id, city_id, description, amenities, area, created_at, updated_at
+  title = $1,
+  featured_image = $2,
+  bedrooms = $3,
+  bathrooms = $4,
+  type_rent = $5,
+  price = $6,
+  province_id = $7,
+  city_id = $8,
+  description = $9,
+  amenities = $10,
+  area = $11,
+  security_deposit = $12,
+  cleaning_fee = $13,
+  service_fee = $14,
+  weekly_discount = $15,
+  monthly_discount = $16,
+  tax_rate = $17,
+  updated_at = $18
+WHERE id = $19 AND updated_at = $20
+RETURNING id, owner_id, title, featured_image, bedrooms, bathrooms, type_rent, price, province_id, city_id, description, amenities, area, created_at, updated_at, security_deposit, cleaning_fee, service_fee, weekly_discount, monthly_discount, tax_rate, status, moderation_note, deleted_at
 `
 
 type UpdateHouseParams struct {
-	ID            uuid.UUID `json:"id"`
-	Title         string    `json:"title"`
-	FeaturedImage string    `json:"featured_image"`
-	Bedrooms      int32     `json:"bedrooms"`
-	Bathrooms     int32     `json:"bathrooms"`
-	TypeRent      string    `json:"type_rent"`
-	Price         int64     `json:"price"`
-	ProvinceID    int32     `json:"province_id"`
-	CityID        int32     `json:"city_id"`
-	Description   string    `json:"description"`
-	Amenities     string    `json:"amenities"`
-	Area          int32     `json:"area"`
+	Title             string    `json:"title"`
+	FeaturedImage     string    `json:"featured_image"`
+	Bedrooms          int32     `json:"bedrooms"`
+	Bathrooms         int32     `json:"bathrooms"`
+	TypeRent          string    `json:"type_rent"`
+	Price             int64     `json:"price"`
+	ProvinceID        int32     `json:"province_id"`
+	CityID            int32     `json:"city_id"`
+	Description       string    `json:"description"`
+	Amenities         string    `json:"amenities"`
+	Area              int32     `json:"area"`
+	SecurityDeposit   int64     `json:"security_deposit"`
+	CleaningFee       int64     `json:"cleaning_fee"`
+	ServiceFee        int64     `json:"service_fee"`
+	WeeklyDiscount    int32     `json:"weekly_discount"`
+	MonthlyDiscount   int32     `json:"monthly_discount"`
+	TaxRate           int32     `json:"tax_rate"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	ID                uuid.UUID `json:"id"`
+	ExpectedUpdatedAt time.Time `json:"expected_updated_at"`
 }
 
 func (q *Queries) UpdateHouse(ctx context.Context, arg UpdateHouseParams) (Home, error) {
 	row := q.db.QueryRowContext(ctx, updateHouse,
-		arg.ID,
 		arg.Title,
 		arg.FeaturedImage,
 		arg.Bedrooms,
@@ -387,6 +525,15 @@ func (q *Queries) UpdateHouse(ctx context.Context, arg UpdateHouseParams) (Home,
 		arg.Description,
 		arg.Amenities,
 		arg.Area,
+		arg.SecurityDeposit,
+		arg.CleaningFee,
+		arg.ServiceFee,
+		arg.WeeklyDiscount,
+		arg.MonthlyDiscount,
+		arg.TaxRate,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.ExpectedUpdatedAt,
 	)
 	var i Home
 	err := row.Scan(
@@ -405,6 +552,138 @@ func (q *Queries) UpdateHouse(ctx context.Context, arg UpdateHouseParams) (Home,
 		&i.Area,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SecurityDeposit,
+		&i.CleaningFee,
+		&i.ServiceFee,
+		&i.WeeklyDiscount,
+		&i.MonthlyDiscount,
+		&i.TaxRate,
+		&i.Status,
+		&i.ModerationNote,
+		&i.DeletedAt,
 	)
 	return i, err
 }
+
+const restoreHouse = `-- name: RestoreHouse :execrows
+UPDATE homes
+SET
+  status = 'draft',
+  deleted_at = NULL,
+  updated_at = $1
+WHERE id = $2 AND updated_at = $3
+`
+
+type RestoreHouseParams struct {
+	UpdatedAt         time.Time `json:"updated_at"`
+	ID                uuid.UUID `json:"id"`
+	ExpectedUpdatedAt time.Time `json:"expected_updated_at"`
+}
+
+func (q *Queries) RestoreHouse(ctx context.Context, arg RestoreHouseParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreHouse, arg.UpdatedAt, arg.ID, arg.ExpectedUpdatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const softDeleteHouse = `-- name: SoftDeleteHouse :exec
+UPDATE homes
+SET
+  status = 'archived',
+  deleted_at = $2,
+  updated_at = $2
+WHERE id = $1
+`
+
+type SoftDeleteHouseParams struct {
+	ID        uuid.UUID    `json:"id"`
+	DeletedAt sql.NullTime `json:"deleted_at"`
+}
+
+func (q *Queries) SoftDeleteHouse(ctx context.Context, arg SoftDeleteHouseParams) error {
+	_, err := q.db.ExecContext(ctx, softDeleteHouse, arg.ID, arg.DeletedAt)
+	return err
+}
+
+const softDeleteHouseByOwner = `-- name: SoftDeleteHouseByOwner :exec
+UPDATE homes
+SET
+  status = 'archived',
+  deleted_at = $2,
+  updated_at = $2
+WHERE owner_id = $1 AND deleted_at IS NULL
+`
+
+type SoftDeleteHouseByOwnerParams struct {
+	OwnerID   uuid.UUID    `json:"owner_id"`
+	DeletedAt sql.NullTime `json:"deleted_at"`
+}
+
+func (q *Queries) SoftDeleteHouseByOwner(ctx context.Context, arg SoftDeleteHouseByOwnerParams) error {
+	_, err := q.db.ExecContext(ctx, softDeleteHouseByOwner, arg.OwnerID, arg.DeletedAt)
+	return err
+}
+
+const updateHousePrimaryRentalPlan = `-- name: UpdateHousePrimaryRentalPlan :execrows
+UPDATE homes
+SET
+  type_rent = $1,
+  price = $2,
+  updated_at = $3
+WHERE id = $4 AND updated_at = $5
+`
+
+type UpdateHousePrimaryRentalPlanParams struct {
+	TypeRent          string    `json:"type_rent"`
+	Price             int64     `json:"price"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	ID                uuid.UUID `json:"id"`
+	ExpectedUpdatedAt time.Time `json:"expected_updated_at"`
+}
+
+func (q *Queries) UpdateHousePrimaryRentalPlan(ctx context.Context, arg UpdateHousePrimaryRentalPlanParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateHousePrimaryRentalPlan,
+		arg.TypeRent,
+		arg.Price,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.ExpectedUpdatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateHouseStatus = `-- name: UpdateHouseStatus :execrows
+UPDATE homes
+SET
+  status = $1,
+  moderation_note = $2,
+  updated_at = $3
+WHERE id = $4 AND updated_at = $5
+`
+
+type UpdateHouseStatusParams struct {
+	Status            string    `json:"status"`
+	ModerationNote    string    `json:"moderation_note"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	ID                uuid.UUID `json:"id"`
+	ExpectedUpdatedAt time.Time `json:"expected_updated_at"`
+}
+
+func (q *Queries) UpdateHouseStatus(ctx context.Context, arg UpdateHouseStatusParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateHouseStatus,
+		arg.Status,
+		arg.ModerationNote,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.ExpectedUpdatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
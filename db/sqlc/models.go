@@ -3,27 +3,126 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type AdminAuditLog struct {
+	ID         uuid.UUID       `json:"id"`
+	AdminID    uuid.UUID       `json:"admin_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   uuid.UUID       `json:"target_id"`
+	Detail     json.RawMessage `json:"detail"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type Conversation struct {
+	ID            uuid.UUID     `json:"id"`
+	TenantID      uuid.UUID     `json:"tenant_id"`
+	OwnerID       uuid.UUID     `json:"owner_id"`
+	HouseID       uuid.NullUUID `json:"house_id"`
+	TransactionID uuid.NullUUID `json:"transaction_id"`
+	LastMessageAt time.Time     `json:"last_message_at"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+type Favorite struct {
+	UserID    uuid.UUID `json:"user_id"`
+	HouseID   uuid.UUID `json:"house_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Home struct {
-	ID            uuid.UUID `json:"id"`
-	OwnerID       uuid.UUID `json:"owner_id"`
-	Title         string    `json:"title"`
-	FeaturedImage string    `json:"featured_image"`
-	Bedrooms      int32     `json:"bedrooms"`
-	Bathrooms     int32     `json:"bathrooms"`
-	TypeRent      string    `json:"type_rent"`
-	Price         int64     `json:"price"`
-	ProvinceID    int32     `json:"province_id"`
-	CityID        int32     `json:"city_id"`
-	Description   string    `json:"description"`
-	Amenities     string    `json:"amenities"`
-	Area          int32     `json:"area"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID              uuid.UUID    `json:"id"`
+	OwnerID         uuid.UUID    `json:"owner_id"`
+	Title           string       `json:"title"`
+	FeaturedImage   string       `json:"featured_image"`
+	Bedrooms        int32        `json:"bedrooms"`
+	Bathrooms       int32        `json:"bathrooms"`
+	TypeRent        string       `json:"type_rent"`
+	Price           int64        `json:"price"`
+	ProvinceID      int32        `json:"province_id"`
+	CityID          int32        `json:"city_id"`
+	Description     string       `json:"description"`
+	Amenities       string       `json:"amenities"`
+	Area            int32        `json:"area"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+	SecurityDeposit int64        `json:"security_deposit"`
+	CleaningFee     int64        `json:"cleaning_fee"`
+	ServiceFee      int64        `json:"service_fee"`
+	WeeklyDiscount  int32        `json:"weekly_discount"`
+	MonthlyDiscount int32        `json:"monthly_discount"`
+	TaxRate         int32        `json:"tax_rate"`
+	Status          string       `json:"status"`
+	ModerationNote  string       `json:"moderation_note"`
+	DeletedAt       sql.NullTime `json:"deleted_at"`
+}
+
+type HouseImport struct {
+	ID             uuid.UUID       `json:"id"`
+	OwnerID        uuid.UUID       `json:"owner_id"`
+	Mode           string          `json:"mode"`
+	Status         string          `json:"status"`
+	Rows           json.RawMessage `json:"rows"`
+	Images         []byte          `json:"images"`
+	TotalRows      int32           `json:"total_rows"`
+	ProcessedRows  int32           `json:"processed_rows"`
+	CreatedRows    int32           `json:"created_rows"`
+	Errors         json.RawMessage `json:"errors"`
+	LeaseUntil     time.Time       `json:"lease_until"`
+	FinishedAt     sql.NullTime    `json:"finished_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	UploadedImages json.RawMessage `json:"uploaded_images"`
+}
+
+type HousePriceRule struct {
+	ID        uuid.UUID    `json:"id"`
+	HouseID   uuid.UUID    `json:"house_id"`
+	Name      string       `json:"name"`
+	StartDate sql.NullTime `json:"start_date"`
+	EndDate   sql.NullTime `json:"end_date"`
+	Weekdays  string       `json:"weekdays"`
+	Price     int64        `json:"price"`
+	MinStay   int32        `json:"min_stay"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type HouseRentalPlan struct {
+	ID          uuid.UUID `json:"id"`
+	HouseID     uuid.UUID `json:"house_id"`
+	Unit        string    `json:"unit"`
+	Price       int64     `json:"price"`
+	MinDuration int32     `json:"min_duration"`
+	MaxDuration int32     `json:"max_duration"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type HouseView struct {
+	HouseID   uuid.UUID `json:"house_id"`
+	ViewedOn  time.Time `json:"viewed_on"`
+	ViewCount int64     `json:"view_count"`
+}
+
+type IdempotencyKey struct {
+	UserID              uuid.UUID `json:"user_id"`
+	Key                 string    `json:"key"`
+	Fingerprint         string    `json:"fingerprint"`
+	Status              string    `json:"status"`
+	ResponseCode        int32     `json:"response_code"`
+	ResponseContentType string    `json:"response_content_type"`
+	ResponseBody        []byte    `json:"response_body"`
+	LockedUntil         time.Time `json:"locked_until"`
+	ExpiresAt           time.Time `json:"expires_at"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
 }
 
 type Image struct {
@@ -33,6 +132,108 @@ type Image struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type Message struct {
+	ID             uuid.UUID    `json:"id"`
+	ConversationID uuid.UUID    `json:"conversation_id"`
+	SenderID       uuid.UUID    `json:"sender_id"`
+	Body           string       `json:"body"`
+	ReadAt         sql.NullTime `json:"read_at"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+type Notification struct {
+	ID        uuid.UUID       `json:"id"`
+	UserID    uuid.UUID       `json:"user_id"`
+	Type      string          `json:"type"`
+	Message   string          `json:"message"`
+	Data      json.RawMessage `json:"data"`
+	ReadAt    sql.NullTime    `json:"read_at"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type NotificationPreference struct {
+	UserID             uuid.UUID `json:"user_id"`
+	Language           string    `json:"language"`
+	EmailNewBooking    bool      `json:"email_new_booking"`
+	EmailPaymentProof  bool      `json:"email_payment_proof"`
+	EmailBookingStatus bool      `json:"email_booking_status"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type PaymentCharge struct {
+	ID            uuid.UUID `json:"id"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+	Provider      string    `json:"provider"`
+	ProviderRef   string    `json:"provider_ref"`
+	Amount        int64     `json:"amount"`
+	Status        string    `json:"status"`
+	RedirectUrl   string    `json:"redirect_url"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type PaymentSubmission struct {
+	ID              uuid.UUID `json:"id"`
+	TransactionID   uuid.UUID `json:"transaction_id"`
+	PaymentProof    string    `json:"payment_proof"`
+	Amount          int64     `json:"amount"`
+	TransferDate    time.Time `json:"transfer_date"`
+	PaymentMethod   string    `json:"payment_method"`
+	Notes           string    `json:"notes"`
+	Status          string    `json:"status"`
+	RejectionReason string    `json:"rejection_reason"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type Review struct {
+	ID            uuid.UUID    `json:"id"`
+	TransactionID uuid.UUID    `json:"transaction_id"`
+	HouseID       uuid.UUID    `json:"house_id"`
+	TenantID      uuid.UUID    `json:"tenant_id"`
+	OwnerID       uuid.UUID    `json:"owner_id"`
+	Rating        int32        `json:"rating"`
+	Comment       string       `json:"comment"`
+	OwnerReply    string       `json:"owner_reply"`
+	RepliedAt     sql.NullTime `json:"replied_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+type Role struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type RolePermission struct {
+	RoleName       string `json:"role_name"`
+	PermissionName string `json:"permission_name"`
+}
+
+type SavedSearch struct {
+	ID             uuid.UUID       `json:"id"`
+	UserID         uuid.UUID       `json:"user_id"`
+	Name           string          `json:"name"`
+	Filter         json.RawMessage `json:"filter"`
+	AlertChannel   string          `json:"alert_channel"`
+	AlertFrequency string          `json:"alert_frequency"`
+	LastAlertedAt  time.Time       `json:"last_alerted_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+type SavedSearchMatch struct {
+	SavedSearchID uuid.UUID    `json:"saved_search_id"`
+	HouseID       uuid.UUID    `json:"house_id"`
+	AlertedAt     sql.NullTime `json:"alerted_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
 type Transaction struct {
 	ID            uuid.UUID `json:"id"`
 	TenantID      uuid.UUID `json:"tenant_id"`
@@ -46,19 +247,68 @@ type Transaction struct {
 	TimeRent      string    `json:"time_rent"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
+	RentalUnit    string    `json:"rental_unit"`
+}
+
+type TransactionLineItem struct {
+	ID            uuid.UUID `json:"id"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+	Kind          string    `json:"kind"`
+	Description   string    `json:"description"`
+	Quantity      int32     `json:"quantity"`
+	UnitPrice     int64     `json:"unit_price"`
+	Amount        int64     `json:"amount"`
+	CreatedAt     time.Time `json:"created_at"`
+	Position      int32     `json:"position"`
 }
 
 type User struct {
-	ID          uuid.UUID `json:"id"`
-	Fullname    string    `json:"fullname"`
-	Username    string    `json:"username"`
-	Email       string    `json:"email"`
-	Role        string    `json:"role"`
-	Gender      string    `json:"gender"`
-	PhoneNumber string    `json:"phone_number"`
-	Password    string    `json:"password"`
-	Address     string    `json:"address"`
-	Avatar      string    `json:"avatar"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                uuid.UUID    `json:"id"`
+	Fullname          string       `json:"fullname"`
+	Username          string       `json:"username"`
+	Email             string       `json:"email"`
+	Role              string       `json:"role"`
+	Gender            string       `json:"gender"`
+	PhoneNumber       string       `json:"phone_number"`
+	Password          string       `json:"password"`
+	Address           string       `json:"address"`
+	Avatar            string       `json:"avatar"`
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+	SuspendedAt       sql.NullTime `json:"suspended_at"`
+	SuspensionReason  string       `json:"suspension_reason"`
+	DeletedAt         sql.NullTime `json:"deleted_at"`
+	ContactVisibility string       `json:"contact_visibility"`
+}
+
+type UserRole struct {
+	UserID    uuid.UUID `json:"user_id"`
+	RoleName  string    `json:"role_name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Webhook struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Url       string    `json:"url"`
+	Secret    string    `json:"secret"`
+	Events    []string  `json:"events"`
+	IsGlobal  bool      `json:"is_global"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id"`
+	WebhookID      uuid.UUID       `json:"webhook_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode int32           `json:"last_status_code"`
+	LastError      string          `json:"last_error"`
+	DeliveredAt    sql.NullTime    `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
 }
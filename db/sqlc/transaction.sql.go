@@ -10,6 +10,44 @@ import (
 	"github.com/google/uuid"
 )
 
+const countUpcomingHouseTransaction = `-- name: CountUpcomingHouseTransaction :one
+SELECT COUNT(*) FROM transactions
+WHERE house_id = $1
+  AND payment_status IN ('waiting-payment', 'waiting-approve', 'approved')
+  AND check_out > $2
+`
+
+type CountUpcomingHouseTransactionParams struct {
+	HouseID  uuid.UUID `json:"house_id"`
+	CheckOut time.Time `json:"check_out"`
+}
+
+func (q *Queries) CountUpcomingHouseTransaction(ctx context.Context, arg CountUpcomingHouseTransactionParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUpcomingHouseTransaction, arg.HouseID, arg.CheckOut)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUpcomingUserTransaction = `-- name: CountUpcomingUserTransaction :one
+SELECT COUNT(*) FROM transactions
+WHERE (tenant_id = $1 OR owner_id = $1)
+  AND payment_status IN ('waiting-payment', 'waiting-approve', 'approved')
+  AND check_out > $2
+`
+
+type CountUpcomingUserTransactionParams struct {
+	TenantID uuid.UUID `json:"tenant_id"`
+	CheckOut time.Time `json:"check_out"`
+}
+
+func (q *Queries) CountUpcomingUserTransaction(ctx context.Context, arg CountUpcomingUserTransactionParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUpcomingUserTransaction, arg.TenantID, arg.CheckOut)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createTransaction = `-- name: CreateTransaction :one
 INSERT INTO transactions (
   id,
@@ -21,10 +59,11 @@ INSERT INTO transactions (
   total_payment,
   check_in,
   check_out,
-  time_rent
+  time_rent,
+  rental_unit
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
-) RETURNING id, tenant_id, owner_id, house_id, payment_status, payment_proof, total_payment, check_in, check_out, time_rent, created_at, updated_at
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING id, tenant_id, owner_id, house_id, payment_status, payment_proof, total_payment, check_in, check_out, time_rent, created_at, updated_at, rental_unit
 `
 
 type CreateTransactionParams struct {
@@ -38,6 +77,7 @@ type CreateTransactionParams struct {
 	CheckIn       time.Time `json:"check_in"`
 	CheckOut      time.Time `json:"check_out"`
 	TimeRent      string    `json:"time_rent"`
+	RentalUnit    string    `json:"rental_unit"`
 }
 
 func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error) {
@@ -52,6 +92,7 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		arg.CheckIn,
 		arg.CheckOut,
 		arg.TimeRent,
+		arg.RentalUnit,
 	)
 	var i Transaction
 	err := row.Scan(
@@ -67,6 +108,59 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		&i.TimeRent,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RentalUnit,
+	)
+	return i, err
+}
+
+const createTransactionLineItem = `-- name: CreateTransactionLineItem :one
+INSERT INTO transaction_line_items (
+  id,
+  transaction_id,
+  kind,
+  description,
+  quantity,
+  unit_price,
+  amount,
+  position
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, transaction_id, kind, description, quantity, unit_price, amount, created_at, position
+`
+
+type CreateTransactionLineItemParams struct {
+	ID            uuid.UUID `json:"id"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+	Kind          string    `json:"kind"`
+	Description   string    `json:"description"`
+	Quantity      int32     `json:"quantity"`
+	UnitPrice     int64     `json:"unit_price"`
+	Amount        int64     `json:"amount"`
+	Position      int32     `json:"position"`
+}
+
+func (q *Queries) CreateTransactionLineItem(ctx context.Context, arg CreateTransactionLineItemParams) (TransactionLineItem, error) {
+	row := q.db.QueryRowContext(ctx, createTransactionLineItem,
+		arg.ID,
+		arg.TransactionID,
+		arg.Kind,
+		arg.Description,
+		arg.Quantity,
+		arg.UnitPrice,
+		arg.Amount,
+		arg.Position,
+	)
+	var i TransactionLineItem
+	err := row.Scan(
+		&i.ID,
+		&i.TransactionID,
+		&i.Kind,
+		&i.Description,
+		&i.Quantity,
+		&i.UnitPrice,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Position,
 	)
 	return i, err
 }
@@ -81,6 +175,248 @@ func (q *Queries) DeleteTransaction(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const expireStaleTransaction = `-- name: ExpireStaleTransaction :many
+UPDATE transactions
+SET
+  payment_status = 'cancel',
+  updated_at = $1
+WHERE id IN (
+  SELECT id FROM transactions
+  WHERE payment_status = 'waiting-payment' AND created_at < $2
+  ORDER BY created_at
+  LIMIT $3
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, tenant_id, owner_id, house_id, payment_status, payment_proof, total_payment, check_in, check_out, time_rent, created_at, updated_at, rental_unit
+`
+
+type ExpireStaleTransactionParams struct {
+	UpdatedAt     time.Time `json:"updated_at"`
+	CreatedBefore time.Time `json:"created_before"`
+	BatchSize     int32     `json:"batch_size"`
+}
+
+func (q *Queries) ExpireStaleTransaction(ctx context.Context, arg ExpireStaleTransactionParams) ([]Transaction, error) {
+	rows, err := q.db.QueryContext(ctx, expireStaleTransaction, arg.UpdatedAt, arg.CreatedBefore, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.OwnerID,
+			&i.HouseID,
+			&i.PaymentStatus,
+			&i.PaymentProof,
+			&i.TotalPayment,
+			&i.CheckIn,
+			&i.CheckOut,
+			&i.TimeRent,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RentalUnit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const expireTransactionById = `-- name: ExpireTransactionById :execrows
+UPDATE transactions
+SET
+  payment_status = 'cancel',
+  updated_at = $2
+WHERE id = $1 AND payment_status = 'waiting-payment'
+`
+
+type ExpireTransactionByIdParams struct {
+	ID        uuid.UUID `json:"id"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (q *Queries) ExpireTransactionById(ctx context.Context, arg ExpireTransactionByIdParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, expireTransactionById, arg.ID, arg.UpdatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getTransactionById = `-- name: GetTransactionById :one
+SELECT 
+  id,
+  tenant_id,
+  owner_id,
+  house_id,
+  payment_status,
+  payment_proof,
+  total_payment,
+  check_in,
+  check_out,
+  time_rent,
+  created_at,
+  updated_at,
+  rental_unit
+FROM transactions
+WHERE transactions.id = $1 LIMIT 1
+`
+
+func (q *Queries) GetTransactionById(ctx context.Context, id uuid.UUID) (Transaction, error) {
+	row := q.db.QueryRowContext(ctx, getTransactionById, id)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.OwnerID,
+		&i.HouseID,
+		&i.PaymentStatus,
+		&i.PaymentProof,
+		&i.TotalPayment,
+		&i.CheckIn,
+		&i.CheckOut,
+		&i.TimeRent,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RentalUnit,
+	)
+	return i, err
+}
+
+const hasTenantBookedHouse = `-- name: HasTenantBookedHouse :one
+SELECT EXISTS (
+  SELECT 1 FROM transactions
+  WHERE house_id = $1 AND tenant_id = $2
+)
+`
+
+type HasTenantBookedHouseParams struct {
+	HouseID  uuid.UUID `json:"house_id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+}
+
+func (q *Queries) HasTenantBookedHouse(ctx context.Context, arg HasTenantBookedHouseParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, hasTenantBookedHouse, arg.HouseID, arg.TenantID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listTransactionLineItem = `-- name: ListTransactionLineItem :many
+SELECT id, transaction_id, kind, description, quantity, unit_price, amount, created_at, position FROM transaction_line_items
+WHERE transaction_id = $1
+ORDER BY position
+`
+
+func (q *Queries) ListTransactionLineItem(ctx context.Context, transactionID uuid.UUID) ([]TransactionLineItem, error) {
+	rows, err := q.db.QueryContext(ctx, listTransactionLineItem, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TransactionLineItem
+	for rows.Next() {
+		var i TransactionLineItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.TransactionID,
+			&i.Kind,
+			&i.Description,
+			&i.Quantity,
+			&i.UnitPrice,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Position,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransactionByUser = `-- name: ListTransactionByUser :many
+SELECT id, tenant_id, owner_id, house_id, payment_status, payment_proof, total_payment, check_in, check_out, time_rent, created_at, updated_at, rental_unit FROM transactions
+WHERE tenant_id = $1 OR owner_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListTransactionByUser(ctx context.Context, tenantID uuid.UUID) ([]Transaction, error) {
+	rows, err := q.db.QueryContext(ctx, listTransactionByUser, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.OwnerID,
+			&i.HouseID,
+			&i.PaymentStatus,
+			&i.PaymentProof,
+			&i.TotalPayment,
+			&i.CheckIn,
+			&i.CheckOut,
+			&i.TimeRent,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RentalUnit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateOpenTransactionStatus = `-- name: UpdateOpenTransactionStatus :execrows
+UPDATE transactions
+SET
+  payment_status = $2,
+  updated_at = $3
+WHERE id = $1 AND payment_status IN ('waiting-payment', 'waiting-approve', 'rejected')
+`
+
+type UpdateOpenTransactionStatusParams struct {
+	ID            uuid.UUID `json:"id"`
+	PaymentStatus string    `json:"payment_status"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (q *Queries) UpdateOpenTransactionStatus(ctx context.Context, arg UpdateOpenTransactionStatusParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateOpenTransactionStatus, arg.ID, arg.PaymentStatus, arg.UpdatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateTransactionPaymentProofById = `-- name: UpdateTransactionPaymentProofById :exec
 UPDATE transactions 
 SET 
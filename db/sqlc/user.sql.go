@@ -5,11 +5,39 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const anonymizeUserById = `-- name: AnonymizeUserById :exec
+UPDATE users 
+SET 
+  fullname = 'Deleted user',
+  username = $2,
+  email = '',
+  gender = '',
+  phone_number = '',
+  password = '',
+  address = '',
+  avatar = '',
+  deleted_at = $3,
+  updated_at = $3
+WHERE id = $1
+`
+
+type AnonymizeUserByIdParams struct {
+	ID        uuid.UUID    `json:"id"`
+	Username  string       `json:"username"`
+	DeletedAt sql.NullTime `json:"deleted_at"`
+}
+
+func (q *Queries) AnonymizeUserById(ctx context.Context, arg AnonymizeUserByIdParams) error {
+	_, err := q.db.ExecContext(ctx, anonymizeUserById, arg.ID, arg.Username, arg.DeletedAt)
+	return err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
   id,
@@ -91,23 +119,25 @@ SELECT
   address, 
   avatar,
   created_at, 
-  updated_at
+  updated_at,
+  contact_visibility
 FROM users
 WHERE users.id = $1 LIMIT 1
 `
 
 type GetUserByIdRow struct {
-	ID          uuid.UUID `json:"id"`
-	Fullname    string    `json:"fullname"`
-	Username    string    `json:"username"`
-	Email       string    `json:"email"`
-	Role        string    `json:"role"`
-	Gender      string    `json:"gender"`
-	PhoneNumber string    `json:"phone_number"`
-	Address     string    `json:"address"`
-	Avatar      string    `json:"avatar"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                uuid.UUID `json:"id"`
+	Fullname          string    `json:"fullname"`
+	Username          string    `json:"username"`
+	Email             string    `json:"email"`
+	Role              string    `json:"role"`
+	Gender            string    `json:"gender"`
+	PhoneNumber       string    `json:"phone_number"`
+	Address           string    `json:"address"`
+	Avatar            string    `json:"avatar"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	ContactVisibility string    `json:"contact_visibility"`
 }
 
 func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (GetUserByIdRow, error) {
@@ -125,6 +155,7 @@ func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (GetUserByIdRow
 		&i.Avatar,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ContactVisibility,
 	)
 	return i, err
 }
@@ -142,7 +173,11 @@ SELECT
   address, 
   avatar,
   created_at, 
-  updated_at
+  updated_at,
+  suspended_at,
+  suspension_reason,
+  deleted_at,
+  contact_visibility
 FROM users
 WHERE users.username = $1 LIMIT 1
 `
@@ -163,6 +198,10 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.Avatar,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SuspendedAt,
+		&i.SuspensionReason,
+		&i.DeletedAt,
+		&i.ContactVisibility,
 	)
 	return i, err
 }
@@ -186,38 +225,62 @@ func (q *Queries) UpdateUserAvatarById(ctx context.Context, arg UpdateUserAvatar
 	return err
 }
 
-const updateUserById = `-- name: UpdateUserById :exec
+const updateUserById = `-- name: UpdateUserById :one
 UPDATE users
 SET
-  fullname = $2,
-  email = $3,
-  gender = $4,
-  phone_number = $5,
-  address = $6,
-  updated_at = $7
-WHERE id = $1
+  fullname = $1,
+  email = $2,
+  gender = $3,
+  phone_number = $4,
+  address = $5,
+  updated_at = $6
+WHERE id = $7 AND updated_at = $8
+RETURNING updated_at
 `
 
 type UpdateUserByIdParams struct {
-	ID          uuid.UUID `json:"id"`
-	Fullname    string    `json:"fullname"`
-	Email       string    `json:"email"`
-	Gender      string    `json:"gender"`
-	PhoneNumber string    `json:"phone_number"`
-	Address     string    `json:"address"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Fullname          string    `json:"fullname"`
+	Email             string    `json:"email"`
+	Gender            string    `json:"gender"`
+	PhoneNumber       string    `json:"phone_number"`
+	Address           string    `json:"address"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	ID                uuid.UUID `json:"id"`
+	ExpectedUpdatedAt time.Time `json:"expected_updated_at"`
 }
 
-func (q *Queries) UpdateUserById(ctx context.Context, arg UpdateUserByIdParams) error {
-	_, err := q.db.ExecContext(ctx, updateUserById,
-		arg.ID,
+func (q *Queries) UpdateUserById(ctx context.Context, arg UpdateUserByIdParams) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, updateUserById,
 		arg.Fullname,
 		arg.Email,
 		arg.Gender,
 		arg.PhoneNumber,
 		arg.Address,
 		arg.UpdatedAt,
+		arg.ID,
+		arg.ExpectedUpdatedAt,
 	)
+	var updated_at time.Time
+	err := row.Scan(&updated_at)
+	return updated_at, err
+}
+
+const updateUserContactVisibilityById = `-- name: UpdateUserContactVisibilityById :exec
+UPDATE users 
+SET 
+  contact_visibility = $2,
+  updated_at = $3
+WHERE id = $1
+`
+
+type UpdateUserContactVisibilityByIdParams struct {
+	ID                uuid.UUID `json:"id"`
+	ContactVisibility string    `json:"contact_visibility"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+func (q *Queries) UpdateUserContactVisibilityById(ctx context.Context, arg UpdateUserContactVisibilityByIdParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserContactVisibilityById, arg.ID, arg.ContactVisibility, arg.UpdatedAt)
 	return err
 }
 
@@ -239,3 +302,29 @@ func (q *Queries) UpdateUserPasswordById(ctx context.Context, arg UpdateUserPass
 	_, err := q.db.ExecContext(ctx, updateUserPasswordById, arg.ID, arg.Password, arg.UpdatedAt)
 	return err
 }
+
+const updateUserSuspensionById = `-- name: UpdateUserSuspensionById :exec
+UPDATE users 
+SET 
+  suspended_at = $2,
+  suspension_reason = $3,
+  updated_at = $4
+WHERE id = $1
+`
+
+type UpdateUserSuspensionByIdParams struct {
+	ID               uuid.UUID    `json:"id"`
+	SuspendedAt      sql.NullTime `json:"suspended_at"`
+	SuspensionReason string       `json:"suspension_reason"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+}
+
+func (q *Queries) UpdateUserSuspensionById(ctx context.Context, arg UpdateUserSuspensionByIdParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserSuspensionById,
+		arg.ID,
+		arg.SuspendedAt,
+		arg.SuspensionReason,
+		arg.UpdatedAt,
+	)
+	return err
+}
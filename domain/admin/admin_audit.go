@@ -0,0 +1,39 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+
+	sqlc "gubuk-service/db/sqlc"
+	"gubuk-service/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// recordAction add an admin action to the audit trail, it's meant to be called within
+// the same database transaction as the action so one is never stored without the other
+func recordAction(c *gin.Context, q *sqlc.Queries, action string, targetType string, targetID uuid.UUID, detail map[string]interface{}) error {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+
+	adminID, err := uuid.Parse(userPayload.UserID)
+	if err != nil {
+		return err
+	}
+
+	detailJSON, err := json.Marshal(detail)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateAdminAuditLog(context.TODO(), sqlc.CreateAdminAuditLogParams{
+		ID:         uuid.New(),
+		AdminID:    adminID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Detail:     detailJSON,
+	})
+	return err
+}
@@ -0,0 +1,637 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	db "gubuk-service/db"
+	sqlc "gubuk-service/db/sqlc"
+	"gubuk-service/domain/house"
+	"gubuk-service/event"
+	"gubuk-service/util"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const defaultListLimit = 50
+
+// ListUser search users by their name, username or email, filtered by role & suspension
+func ListUser(c *gin.Context) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	listUserQueryBuilder := psql.Select("id", "fullname", "username", "email", "role", "gender", "phone_number", "address", "avatar", "created_at", "updated_at", "suspended_at", "suspension_reason", "ARRAY(SELECT role_name FROM user_roles WHERE user_roles.user_id = users.id ORDER BY role_name)::text[] AS roles").From("users")
+
+	searchFilter := c.Query("q")
+	if searchFilter != "" {
+		listUserQueryBuilder = listUserQueryBuilder.Where(sq.Or{
+			sq.ILike{"fullname": "%" + searchFilter + "%"},
+			sq.ILike{"username": "%" + searchFilter + "%"},
+			sq.ILike{"email": "%" + searchFilter + "%"},
+		})
+	}
+
+	roleFilter := c.Query("role")
+	if roleFilter != "" {
+		listUserQueryBuilder = listUserQueryBuilder.Where("EXISTS (SELECT 1 FROM user_roles WHERE user_roles.user_id = users.id AND user_roles.role_name = ?)", roleFilter)
+	}
+
+	suspendedFilter := c.Query("suspended")
+	if suspendedFilter == "true" {
+		listUserQueryBuilder = listUserQueryBuilder.Where(sq.NotEq{"suspended_at": nil})
+	} else if suspendedFilter == "false" {
+		listUserQueryBuilder = listUserQueryBuilder.Where(sq.Eq{"suspended_at": nil})
+	}
+
+	listUserQueryBuilder = paginate(c, listUserQueryBuilder).OrderBy("created_at DESC")
+
+	listUserQuery, args, err := listUserQueryBuilder.ToSql()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	rows, err := db.DB.QueryContext(context.TODO(), listUserQuery, args...)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer rows.Close()
+	userList := make([]UserRow, 0)
+	for rows.Next() {
+		var i UserRow
+		var suspendedAt sql.NullTime
+		if err := rows.Scan(
+			&i.ID,
+			&i.Fullname,
+			&i.Username,
+			&i.Email,
+			&i.Role,
+			&i.Gender,
+			&i.PhoneNumber,
+			&i.Address,
+			&i.Avatar,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&suspendedAt,
+			&i.SuspensionReason,
+			pq.Array(&i.Roles),
+		); err != nil {
+			util.SendServerError(c, err)
+			return
+		}
+		if suspendedAt.Valid {
+			i.SuspendedAt = &suspendedAt.Time
+		}
+		userList = append(userList, i)
+	}
+	if err := rows.Err(); err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, userList)
+}
+
+// SuspendUser block a user from logging in & using the api
+func SuspendUser(c *gin.Context) {
+	var req ModerationRequest
+	err := c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	suspendedUser, err := getUser(c)
+	if err != nil {
+		util.SendNotFound(c, err)
+		return
+	}
+
+	isAdmin, err := db.Queries.HasUserRole(context.TODO(), sqlc.HasUserRoleParams{
+		UserID:   suspendedUser.ID,
+		RoleName: "admin",
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	if isAdmin {
+		util.SendBadRequest(c, errors.New("an admin could not be suspended"))
+		return
+	}
+
+	updateUserSuspension(c, suspendedUser.ID, ActionSuspendUser, sqlc.UpdateUserSuspensionByIdParams{
+		ID:               suspendedUser.ID,
+		SuspendedAt:      sql.NullTime{Time: time.Now(), Valid: true},
+		SuspensionReason: req.Reason,
+		UpdatedAt:        time.Now(),
+	}, map[string]interface{}{"reason": req.Reason})
+}
+
+// UnsuspendUser lift the suspension of a user
+func UnsuspendUser(c *gin.Context) {
+	suspendedUser, err := getUser(c)
+	if err != nil {
+		util.SendNotFound(c, err)
+		return
+	}
+
+	updateUserSuspension(c, suspendedUser.ID, ActionUnsuspendUser, sqlc.UpdateUserSuspensionByIdParams{
+		ID:        suspendedUser.ID,
+		UpdatedAt: time.Now(),
+	}, nil)
+}
+
+// UnpublishHouse take a house off the public listings, it's owner is told the reason
+func UnpublishHouse(c *gin.Context) {
+	var req ModerationRequest
+	err := c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	moderatedHouse, err := getHouse(c)
+	if err != nil {
+		util.SendNotFound(c, err)
+		return
+	}
+
+	if moderatedHouse.Status != house.HouseStatusPublished && moderatedHouse.Status != house.HouseStatusPendingReview {
+		util.SendBadRequest(c, errors.New("only a published or pending review house could be unpublished"))
+		return
+	}
+
+	updateHouseStatus(c, moderatedHouse, ActionUnpublishHouse, house.HouseStatusUnlisted, req.Reason,
+		moderatedHouse.Title+" is unpublished by an admin: "+req.Reason)
+}
+
+// PublishHouse approve a house pending review or put an unlisted house back on the public listings
+func PublishHouse(c *gin.Context) {
+	moderatedHouse, err := getHouse(c)
+	if err != nil {
+		util.SendNotFound(c, err)
+		return
+	}
+
+	if moderatedHouse.Status != house.HouseStatusPendingReview && moderatedHouse.Status != house.HouseStatusUnlisted {
+		util.SendBadRequest(c, errors.New("only a pending review or unlisted house could be published"))
+		return
+	}
+
+	updateHouseStatus(c, moderatedHouse, ActionPublishHouse, house.HouseStatusPublished, "",
+		moderatedHouse.Title+" is published by an admin")
+}
+
+// RejectHouse send a house pending review back to it's owner as a draft along with the reason
+func RejectHouse(c *gin.Context) {
+	var req ModerationRequest
+	err := c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	moderatedHouse, err := getHouse(c)
+	if err != nil {
+		util.SendNotFound(c, err)
+		return
+	}
+
+	if moderatedHouse.Status != house.HouseStatusPendingReview {
+		util.SendBadRequest(c, errors.New("only a pending review house could be rejected"))
+		return
+	}
+
+	updateHouseStatus(c, moderatedHouse, ActionRejectHouse, house.HouseStatusDraft, req.Reason,
+		moderatedHouse.Title+" is rejected by an admin: "+req.Reason)
+}
+
+// ListHouse return the moderation queue, houses pending review by default, oldest first
+func ListHouse(c *gin.Context) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	listHouseQueryBuilder := psql.Select("homes.id", "title", "featured_image", "status", "moderation_note", "owner_id", "owner.username", "homes.created_at", "homes.updated_at").From("homes").Join("users AS owner ON owner.id = homes.owner_id")
+
+	statusFilter := c.DefaultQuery("status", house.HouseStatusPendingReview)
+	listHouseQueryBuilder = listHouseQueryBuilder.Where(sq.Eq{"status": statusFilter})
+
+	ownerFilter, err := uuid.Parse(c.Query("owner_id"))
+	if err == nil {
+		listHouseQueryBuilder = listHouseQueryBuilder.Where(sq.Eq{"owner_id": ownerFilter})
+	}
+
+	listHouseQueryBuilder = paginate(c, listHouseQueryBuilder).OrderBy("homes.updated_at ASC")
+
+	listHouseQuery, args, err := listHouseQueryBuilder.ToSql()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	rows, err := db.DB.QueryContext(context.TODO(), listHouseQuery, args...)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer rows.Close()
+	houseList := make([]HouseRow, 0)
+	for rows.Next() {
+		var i HouseRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.FeaturedImage,
+			&i.Status,
+			&i.ModerationNote,
+			&i.OwnerID,
+			&i.OwnerUsername,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			util.SendServerError(c, err)
+			return
+		}
+		houseList = append(houseList, i)
+	}
+	if err := rows.Err(); err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, houseList)
+}
+
+// DeleteHouse archive a house which has no upcoming booking, it could be restored by it's owner
+func DeleteHouse(c *gin.Context) {
+	var req ModerationRequest
+	err := c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	deletedHouse, err := getHouse(c)
+	if err != nil {
+		util.SendNotFound(c, err)
+		return
+	}
+
+	deletedAt, err := db.Queries.GetHouseDeletedAt(context.TODO(), deletedHouse.ID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	if deletedAt.Valid {
+		util.SendBadRequest(c, errors.New("house is already deleted"))
+		return
+	}
+
+	err = house.CheckDeletable(deletedHouse.ID)
+	if err != nil {
+		if errors.Is(err, house.ErrUpcomingBooking) {
+			util.SendBadRequest(c, err)
+			return
+		}
+
+		util.SendServerError(c, err)
+		return
+	}
+
+	tx, err := db.DB.BeginTx(context.TODO(), nil)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := db.Queries.WithTx(tx)
+
+	err = recordAction(c, qtx, ActionDeleteHouse, "house", deletedHouse.ID, map[string]interface{}{
+		"reason":   req.Reason,
+		"title":    deletedHouse.Title,
+		"owner_id": deletedHouse.OwnerID,
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = qtx.SoftDeleteHouse(context.TODO(), sqlc.SoftDeleteHouseParams{
+		ID:        deletedHouse.ID,
+		DeletedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, nil)
+}
+
+// ResolveTransaction settle a disputed transaction by forcing it's status, a payment submission
+// waiting to be approved is approved or rejected along with it
+func ResolveTransaction(c *gin.Context) {
+	var req TransactionResolveRequest
+	err := c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.SendNotFound(c, errors.New("transaction with the provided id is not exist"))
+		return
+	}
+
+	resolvedTransaction, err := db.Queries.GetTransactionById(context.TODO(), id)
+	if err != nil {
+		util.SendNotFound(c, errors.New("transaction with the provided id is not exist"))
+		return
+	}
+
+	tx, err := db.DB.BeginTx(context.TODO(), nil)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := db.Queries.WithTx(tx)
+
+	latestSubmission, err := qtx.GetLatestPaymentSubmission(context.TODO(), id)
+	if err == nil && latestSubmission.Status == "waiting-approve" {
+		submissionStatus := "rejected"
+		rejectionReason := req.Note
+		if req.Status == "approved" {
+			submissionStatus = "approved"
+			rejectionReason = ""
+		}
+
+		err = qtx.UpdatePaymentSubmissionStatusById(context.TODO(), sqlc.UpdatePaymentSubmissionStatusByIdParams{
+			ID:              latestSubmission.ID,
+			Status:          submissionStatus,
+			RejectionReason: rejectionReason,
+			UpdatedAt:       time.Now(),
+		})
+	} else if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = qtx.UpdateTransactionStatusById(context.TODO(), sqlc.UpdateTransactionStatusByIdParams{
+		ID:            id,
+		PaymentStatus: req.Status,
+		UpdatedAt:     time.Now(),
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = recordAction(c, qtx, ActionResolveTransaction, "transaction", id, map[string]interface{}{
+		"from_status": resolvedTransaction.PaymentStatus,
+		"to_status":   req.Status,
+		"note":        req.Note,
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	statusChanged := event.Event{
+		Type:       event.TransactionStatusChanged,
+		Recipients: []uuid.UUID{resolvedTransaction.TenantID, resolvedTransaction.OwnerID},
+		OwnerID:    resolvedTransaction.OwnerID,
+		Message:    "Your booking is resolved by an admin as " + req.Status + ": " + req.Note,
+		Data: map[string]interface{}{
+			"transaction_id": resolvedTransaction.ID,
+			"house_id":       resolvedTransaction.HouseID,
+			"payment_status": req.Status,
+			"reason":         req.Note,
+		},
+	}
+	if req.Status == "cancel" {
+		statusChanged.Data["cancel_reason"] = event.CancelReasonAdmin
+	}
+	event.Publish(statusChanged)
+
+	util.SendSuccess(c, nil)
+}
+
+// ListAuditLog return the audit trail of admin actions, latest first
+func ListAuditLog(c *gin.Context) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	listAuditLogQueryBuilder := psql.Select("admin_audit_logs.id", "admin_id", "admin.username", "action", "target_type", "target_id", "detail", "admin_audit_logs.created_at").From("admin_audit_logs").Join("users AS admin ON admin.id = admin_audit_logs.admin_id")
+
+	for _, filter := range []string{"admin_id", "target_id"} {
+		id, err := uuid.Parse(c.Query(filter))
+		if err == nil {
+			listAuditLogQueryBuilder = listAuditLogQueryBuilder.Where(sq.Eq{filter: id})
+		}
+	}
+	for _, filter := range []string{"action", "target_type"} {
+		if c.Query(filter) != "" {
+			listAuditLogQueryBuilder = listAuditLogQueryBuilder.Where(sq.Eq{filter: c.Query(filter)})
+		}
+	}
+
+	listAuditLogQueryBuilder = paginate(c, listAuditLogQueryBuilder).OrderBy("admin_audit_logs.created_at DESC")
+
+	listAuditLogQuery, args, err := listAuditLogQueryBuilder.ToSql()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	rows, err := db.DB.QueryContext(context.TODO(), listAuditLogQuery, args...)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer rows.Close()
+	auditLogList := make([]AuditLogRow, 0)
+	for rows.Next() {
+		var i AuditLogRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AdminID,
+			&i.AdminUsername,
+			&i.Action,
+			&i.TargetType,
+			&i.TargetID,
+			&i.Detail,
+			&i.CreatedAt,
+		); err != nil {
+			util.SendServerError(c, err)
+			return
+		}
+		auditLogList = append(auditLogList, i)
+	}
+	if err := rows.Err(); err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, auditLogList)
+}
+
+func updateUserSuspension(c *gin.Context, userID uuid.UUID, action string, params sqlc.UpdateUserSuspensionByIdParams, detail map[string]interface{}) {
+	tx, err := db.DB.BeginTx(context.TODO(), nil)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := db.Queries.WithTx(tx)
+
+	err = qtx.UpdateUserSuspensionById(context.TODO(), params)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = recordAction(c, qtx, action, "user", userID, detail)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, nil)
+}
+
+func updateHouseStatus(c *gin.Context, moderatedHouse sqlc.GetHouseByIdRow, action string, status string, note string, message string) {
+	tx, err := db.DB.BeginTx(context.TODO(), nil)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := db.Queries.WithTx(tx)
+
+	// the house is only moderated when it's not changed since the moderation is decided on it
+	updatedRows, err := qtx.UpdateHouseStatus(context.TODO(), sqlc.UpdateHouseStatusParams{
+		ID:                moderatedHouse.ID,
+		Status:            status,
+		ModerationNote:    note,
+		UpdatedAt:         time.Now(),
+		ExpectedUpdatedAt: moderatedHouse.UpdatedAt,
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	if updatedRows == 0 {
+		util.SendConflict(c, errors.New("house is changed by it's owner while it's moderated, please review it again"))
+		return
+	}
+
+	err = recordAction(c, qtx, action, "house", moderatedHouse.ID, map[string]interface{}{
+		"from_status": moderatedHouse.Status,
+		"to_status":   status,
+		"reason":      note,
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	event.Publish(event.Event{
+		Type:       event.HouseUpdated,
+		Recipients: []uuid.UUID{moderatedHouse.OwnerID},
+		OwnerID:    moderatedHouse.OwnerID,
+		Message:    message,
+		Data: map[string]interface{}{
+			"house_id": moderatedHouse.ID,
+			"title":    moderatedHouse.Title,
+			"status":   status,
+		},
+	})
+
+	if status == house.HouseStatusPublished {
+		house.AnnouncePublished(moderatedHouse.ID, moderatedHouse.OwnerID, moderatedHouse.Title)
+	}
+
+	util.SendSuccess(c, nil)
+}
+
+// getUser return the user referred by the id param
+func getUser(c *gin.Context) (sqlc.GetUserByIdRow, error) {
+	errNotExist := errors.New("user with the provided id is not exist")
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return sqlc.GetUserByIdRow{}, errNotExist
+	}
+
+	user, err := db.Queries.GetUserById(context.TODO(), id)
+	if err != nil {
+		return sqlc.GetUserByIdRow{}, errNotExist
+	}
+
+	return user, nil
+}
+
+// getHouse return the house referred by the id param
+func getHouse(c *gin.Context) (sqlc.GetHouseByIdRow, error) {
+	errNotExist := errors.New("house with the provided id is not exist")
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return sqlc.GetHouseByIdRow{}, errNotExist
+	}
+
+	house, err := db.Queries.GetHouseById(context.TODO(), id)
+	if err != nil {
+		return sqlc.GetHouseByIdRow{}, errNotExist
+	}
+
+	return house, nil
+}
+
+// paginate apply the limit & offset queries to a list query
+func paginate(c *gin.Context, queryBuilder sq.SelectBuilder) sq.SelectBuilder {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit <= 0 || limit > defaultListLimit {
+		limit = defaultListLimit
+	}
+
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	return queryBuilder.Limit(uint64(limit)).Offset(uint64(offset))
+}
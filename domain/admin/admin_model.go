@@ -0,0 +1,68 @@
+package admin
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actions recorded in the audit trail
+const (
+	ActionSuspendUser        = "user.suspend"
+	ActionUnsuspendUser      = "user.unsuspend"
+	ActionUnpublishHouse     = "house.unpublish"
+	ActionPublishHouse       = "house.publish"
+	ActionRejectHouse        = "house.reject"
+	ActionDeleteHouse        = "house.delete"
+	ActionResolveTransaction = "transaction.resolve"
+)
+
+type ModerationRequest struct {
+	Reason string `form:"reason" json:"reason" binding:"required,max=1000"`
+}
+
+type TransactionResolveRequest struct {
+	Status string `form:"status" json:"status" binding:"required,oneof=approved cancel waiting-payment"`
+	Note   string `form:"note" json:"note" binding:"required,max=1000"`
+}
+
+type UserRow struct {
+	ID               uuid.UUID  `json:"id"`
+	Fullname         string     `json:"fullname"`
+	Username         string     `json:"username"`
+	Email            string     `json:"email"`
+	Role             string     `json:"role"`
+	Gender           string     `json:"gender"`
+	PhoneNumber      string     `json:"phone_number"`
+	Address          string     `json:"address"`
+	Avatar           string     `json:"avatar"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	SuspendedAt      *time.Time `json:"suspended_at"`
+	SuspensionReason string     `json:"suspension_reason"`
+	Roles            []string   `json:"roles"`
+}
+
+type HouseRow struct {
+	ID             uuid.UUID `json:"id"`
+	Title          string    `json:"title"`
+	FeaturedImage  string    `json:"featured_image"`
+	Status         string    `json:"status"`
+	ModerationNote string    `json:"moderation_note"`
+	OwnerID        uuid.UUID `json:"owner_id"`
+	OwnerUsername  string    `json:"owner_username"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type AuditLogRow struct {
+	ID            uuid.UUID       `json:"id"`
+	AdminID       uuid.UUID       `json:"admin_id"`
+	AdminUsername string          `json:"admin_username"`
+	Action        string          `json:"action"`
+	TargetType    string          `json:"target_type"`
+	TargetID      uuid.UUID       `json:"target_id"`
+	Detail        json.RawMessage `json:"detail"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
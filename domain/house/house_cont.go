@@ -2,11 +2,19 @@ package house
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"gubuk-service/config"
+	"gubuk-service/domain/user"
+	"gubuk-service/event"
 	"gubuk-service/media"
+	"gubuk-service/pricing"
 	"gubuk-service/util"
+	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	db "gubuk-service/db"
 	sqlc "gubuk-service/db/sqlc"
@@ -53,26 +61,30 @@ func CreateHouse(c *gin.Context) {
 		return
 	}
 
-	newHouse, err := db.Queries.CreateHouse(context.TODO(), sqlc.CreateHouseParams{
-		ID:            uuid.New(),
-		OwnerID:       ownerID,
-		Title:         req.Title,
-		FeaturedImage: newFeaturedImageURL,
-		Bedrooms:      int32(req.Bedrooms),
-		Bathrooms:     int32(req.Bathrooms),
-		TypeRent:      req.TypeRent,
-		Price:         req.Price,
-		ProvinceID:    int32(req.ProvinceID),
-		CityID:        int32(req.CityID),
-		Amenities:     req.Amenities,
-		Description:   req.Description,
-		Area:          int32(req.Area),
-	})
+	tx, err := db.DB.BeginTx(context.TODO(), nil)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := db.Queries.WithTx(tx)
+
+	newHouse, err := createHouse(qtx, ownerID, req, newFeaturedImageURL)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		util.SendServerError(c, err)
 		return
 	}
 
+	if newHouse.Status == HouseStatusPublished {
+		AnnouncePublished(newHouse.ID, newHouse.OwnerID, newHouse.Title)
+	}
+
 	util.SendSuccess(c, newHouse)
 }
 
@@ -88,42 +100,65 @@ func UpdateHouse(c *gin.Context) {
 		return
 	}
 
-	var req HouseUpdateRequest
-	err = c.Bind(&req)
+	updatedHouse, err := db.Queries.GetHouseById(context.TODO(), id)
+	if err != nil {
+		util.SendBadRequest(c, errors.New("house with the provided id is not exist"))
+		return
+	}
+
+	if userID != updatedHouse.OwnerID.String() {
+		util.SendBadRequest(c, errors.New("you are not own this house, you could not delete it"))
+		return
+	}
+
+	err = checkNotDeleted(id)
 	if err != nil {
 		util.SendBadRequest(c, err)
 		return
 	}
 
-	updatedHouse, err := db.Queries.GetHouseById(context.TODO(), id)
+	err = util.CheckIfMatch(c, updatedHouse.UpdatedAt)
 	if err != nil {
-		util.SendBadRequest(c, errors.New("house with the provided id is not exist"))
+		util.SendPreconditionFailed(c, err)
 		return
 	}
 
-	if userID != updatedHouse.OwnerID.String() {
-		util.SendBadRequest(c, errors.New("you are not own this house, you could not delete it"))
+	req := houseUpdateRequestOf(updatedHouse)
+	err = util.BindPatch(c, &req)
+	if err != nil {
+		util.SendValidationError(c, err)
 		return
 	}
 
+	// the house is only updated when it's not changed since it's read above,
+	// so a concurrent update is never overwritten
 	updateHouseParams := sqlc.UpdateHouseParams{
-		ID:            id,
-		Title:         req.Title,
-		FeaturedImage: updatedHouse.FeaturedImage,
-		Bedrooms:      int32(req.Bedrooms),
-		Bathrooms:     int32(req.Bathrooms),
-		TypeRent:      req.TypeRent,
-		Price:         req.Price,
-		ProvinceID:    int32(req.ProvinceID),
-		CityID:        int32(req.CityID),
-		Description:   req.Description,
-		Amenities:     req.Amenities,
-		Area:          int32(req.Area),
+		ID:                id,
+		Title:             req.Title,
+		FeaturedImage:     updatedHouse.FeaturedImage,
+		Bedrooms:          int32(req.Bedrooms),
+		Bathrooms:         int32(req.Bathrooms),
+		TypeRent:          req.TypeRent,
+		Price:             req.Price,
+		ProvinceID:        int32(req.ProvinceID),
+		CityID:            int32(req.CityID),
+		Description:       req.Description,
+		Amenities:         req.Amenities,
+		Area:              int32(req.Area),
+		SecurityDeposit:   req.SecurityDeposit,
+		CleaningFee:       req.CleaningFee,
+		ServiceFee:        req.ServiceFee,
+		WeeklyDiscount:    req.WeeklyDiscount,
+		MonthlyDiscount:   req.MonthlyDiscount,
+		TaxRate:           req.TaxRate,
+		UpdatedAt:         time.Now(),
+		ExpectedUpdatedAt: updatedHouse.UpdatedAt,
 	}
 
+	// the old featured image is only destroyed once the new one is saved, so a failed update keeps it
 	featuredImage, err := c.FormFile("featured_image")
 	if err == nil {
-		newFeaturedImage, err := media.UpdateMedia("house", updatedHouse.FeaturedImage, featuredImage)
+		newFeaturedImage, err := media.UploadMedia("house", featuredImage)
 		if err != nil {
 			util.SendServerError(c, err)
 			return
@@ -132,12 +167,37 @@ func UpdateHouse(c *gin.Context) {
 		updateHouseParams.FeaturedImage = newFeaturedImage
 	}
 
-	updatedHouseData, err := db.Queries.UpdateHouse(context.TODO(), updateHouseParams)
+	updatedHouseData, err := saveHouseUpdate(updateHouseParams)
 	if err != nil {
+		if updateHouseParams.FeaturedImage != updatedHouse.FeaturedImage {
+			destroyFeaturedImage(updateHouseParams.FeaturedImage)
+		}
+
+		if errors.Is(err, util.ErrPreconditionFailed) {
+			util.SendPreconditionFailed(c, err)
+			return
+		}
+
 		util.SendServerError(c, err)
 		return
 	}
 
+	if updatedHouseData.FeaturedImage != updatedHouse.FeaturedImage {
+		destroyFeaturedImage(updatedHouse.FeaturedImage)
+	}
+
+	event.Publish(event.Event{
+		Type:    event.HouseUpdated,
+		OwnerID: updatedHouseData.OwnerID,
+		Message: updatedHouseData.Title + " is updated",
+		Data: map[string]interface{}{
+			"house_id": updatedHouseData.ID,
+			"title":    updatedHouseData.Title,
+			"price":    updatedHouseData.Price,
+		},
+	})
+
+	util.SetETag(c, updatedHouseData.UpdatedAt)
 	util.SendSuccess(c, updatedHouseData)
 }
 
@@ -164,13 +224,32 @@ func DeleteHouse(c *gin.Context) {
 		return
 	}
 
-	err = media.DestroyMedia(deletedHouse.FeaturedImage)
+	deletedAt, err := db.Queries.GetHouseDeletedAt(context.TODO(), id)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	if deletedAt.Valid {
+		util.SendBadRequest(c, errors.New("house is already deleted"))
+		return
+	}
+
+	err = CheckDeletable(id)
 	if err != nil {
+		if errors.Is(err, ErrUpcomingBooking) {
+			util.SendBadRequest(c, err)
+			return
+		}
+
 		util.SendServerError(c, err)
 		return
 	}
 
-	err = db.Queries.DeleteHouse(context.TODO(), id)
+	// the house is archived along with it's image rather than removed, so the past bookings still refer to it
+	err = db.Queries.SoftDeleteHouse(context.TODO(), sqlc.SoftDeleteHouseParams{
+		ID:        id,
+		DeletedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
 	if err != nil {
 		util.SendServerError(c, err)
 		return
@@ -179,112 +258,200 @@ func DeleteHouse(c *gin.Context) {
 	util.SendSuccess(c, nil)
 }
 
-func GetHouseList(c *gin.Context) {
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	listHouseQueryBuilder := psql.Select("id", "title", "featured_image", "bedrooms", "bathrooms", "type_rent", "price", "province_id", "city_id", "description", "amenities", "area", "created_at", "updated_at").From("homes")
+// RestoreHouse bring a deleted house of the owner back as a draft
+func RestoreHouse(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
 
-	typeRentFilter := c.Query("type_rent")
-	if typeRentFilter != "" {
-		listHouseQueryBuilder = listHouseQueryBuilder.Where(sq.Eq{
-			"type_rent": typeRentFilter,
-		})
+	houseID := c.Param("id")
+	id, err := uuid.Parse(houseID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
 	}
 
-	minBedroomFilter, _ := strconv.Atoi(c.Query("bedrooms"))
-	if minBedroomFilter > 0 {
-		listHouseQueryBuilder = listHouseQueryBuilder.Where(sq.GtOrEq{
-			"bedrooms": minBedroomFilter,
-		})
+	restoredHouse, err := db.Queries.GetHouseById(context.TODO(), id)
+	if err != nil {
+		util.SendBadRequest(c, errors.New("house with the provided id is not exist"))
+		return
 	}
 
-	minBathroomFilter, _ := strconv.Atoi(c.Query("bathrooms"))
-	if minBathroomFilter > 0 {
-		listHouseQueryBuilder = listHouseQueryBuilder.Where(sq.GtOrEq{
-			"bathrooms": minBathroomFilter,
-		})
+	if userID != restoredHouse.OwnerID.String() {
+		util.SendBadRequest(c, errors.New("you are not own this house, you could not restore it"))
+		return
 	}
 
-	maxPriceFilter, _ := strconv.Atoi(c.Query("price"))
-	if maxPriceFilter > 0 {
-		listHouseQueryBuilder = listHouseQueryBuilder.Where(sq.LtOrEq{
-			"price": maxPriceFilter,
-		})
+	deletedAt, err := db.Queries.GetHouseDeletedAt(context.TODO(), id)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
 	}
-
-	provinceFilter, _ := strconv.Atoi(c.Query("province_id"))
-	if provinceFilter > 0 {
-		listHouseQueryBuilder = listHouseQueryBuilder.Where(sq.Eq{
-			"province_id": provinceFilter,
-		})
+	if !deletedAt.Valid {
+		util.SendBadRequest(c, errors.New("house is not deleted"))
+		return
 	}
 
-	cityFilter, _ := strconv.Atoi(c.Query("city_id"))
-	if cityFilter > 0 {
-		listHouseQueryBuilder = listHouseQueryBuilder.Where(sq.Eq{
-			"city_id": cityFilter,
-		})
+	err = util.CheckIfMatch(c, restoredHouse.UpdatedAt)
+	if err != nil {
+		util.SendPreconditionFailed(c, err)
+		return
 	}
 
-	amenitiesFilter := c.Query("amenities")
-	if amenitiesFilter != "" {
-		amenitiesFilters := strings.Split(amenitiesFilter, ",")
-		for _, v := range amenitiesFilters {
-			listHouseQueryBuilder = listHouseQueryBuilder.Where(sq.ILike{"amenities": "%" + v + "%"})
-		}
+	// the house is only restored when it's not changed since it's read above
+	restoredRows, err := db.Queries.RestoreHouse(context.TODO(), sqlc.RestoreHouseParams{
+		ID:                id,
+		UpdatedAt:         time.Now(),
+		ExpectedUpdatedAt: restoredHouse.UpdatedAt,
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	if restoredRows == 0 {
+		util.SendPreconditionFailed(c, util.ErrPreconditionFailed)
+		return
 	}
 
-	limitFilter, _ := strconv.Atoi(c.Query("limit"))
-	if limitFilter > 0 {
-		listHouseQueryBuilder = listHouseQueryBuilder.Limit(uint64(limitFilter))
+	util.SendSuccess(c, gin.H{
+		"status": HouseStatusDraft,
+	})
+}
+
+// CheckDeletable checks a house has no booking left to serve, so it's safe to be deleted
+func CheckDeletable(houseID uuid.UUID) error {
+	upcomingBookings, err := db.Queries.CountUpcomingHouseTransaction(context.TODO(), sqlc.CountUpcomingHouseTransactionParams{
+		HouseID:  houseID,
+		CheckOut: time.Now(),
+	})
+	if err != nil {
+		return err
 	}
 
-	offsetFilter, _ := strconv.Atoi(c.Query("offset"))
-	if offsetFilter > 0 {
-		listHouseQueryBuilder = listHouseQueryBuilder.Offset(uint64(offsetFilter))
+	if upcomingBookings > 0 {
+		return ErrUpcomingBooking
 	}
 
-	listHouseQueryBuilder = listHouseQueryBuilder.OrderBy("created_at DESC")
-	listHouseQuery, args, err := listHouseQueryBuilder.ToSql()
+	return nil
+}
+
+// UpdateHouseStatus move a house of the owner through it's listing lifecycle, publishing a house
+// put it on the moderation queue instead when an admin approval is required
+func UpdateHouseStatus(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	houseID := c.Param("id")
+	id, err := uuid.Parse(houseID)
 	if err != nil {
 		util.SendServerError(c, err)
 		return
 	}
 
-	rows, err := db.DB.QueryContext(context.TODO(), listHouseQuery, args...)
+	var req HouseStatusUpdateRequest
+	err = c.Bind(&req)
 	if err != nil {
-		util.SendServerError(c, err)
+		util.SendBadRequest(c, err)
 		return
 	}
-	defer rows.Close()
-	houseList := make([]sqlc.ListHouseRow, 0)
-	for rows.Next() {
-		var i sqlc.ListHouseRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.Title,
-			&i.FeaturedImage,
-			&i.Bedrooms,
-			&i.Bathrooms,
-			&i.TypeRent,
-			&i.Price,
-			&i.ProvinceID,
-			&i.CityID,
-			&i.Description,
-			&i.Amenities,
-			&i.Area,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			util.SendServerError(c, err)
-			return
+
+	updatedHouse, err := db.Queries.GetHouseById(context.TODO(), id)
+	if err != nil {
+		util.SendBadRequest(c, errors.New("house with the provided id is not exist"))
+		return
+	}
+
+	if userID != updatedHouse.OwnerID.String() {
+		util.SendBadRequest(c, errors.New("you are not own this house, you could not update it"))
+		return
+	}
+
+	err = checkNotDeleted(id)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	err = util.CheckIfMatch(c, updatedHouse.UpdatedAt)
+	if err != nil {
+		util.SendPreconditionFailed(c, err)
+		return
+	}
+
+	allowed := false
+	for _, v := range houseStatusTransitions[updatedHouse.Status] {
+		if v == req.Status {
+			allowed = true
 		}
-		houseList = append(houseList, i)
 	}
-	if err := rows.Close(); err != nil {
+	if !allowed {
+		util.SendBadRequest(c, fmt.Errorf("a %s house could not be moved to %s", updatedHouse.Status, req.Status))
+		return
+	}
+
+	status := req.Status
+	if status == HouseStatusPublished {
+		status = publishStatusOf(updatedHouse.ModerationNote)
+	}
+
+	// the moderation note is kept, so a house taken down by an admin is reviewed again before it's published,
+	// the status is only changed when the house is not changed since it's transition is checked above
+	updatedRows, err := db.Queries.UpdateHouseStatus(context.TODO(), sqlc.UpdateHouseStatusParams{
+		ID:                id,
+		Status:            status,
+		ModerationNote:    updatedHouse.ModerationNote,
+		UpdatedAt:         time.Now(),
+		ExpectedUpdatedAt: updatedHouse.UpdatedAt,
+	})
+	if err != nil {
 		util.SendServerError(c, err)
 		return
 	}
-	if err := rows.Err(); err != nil {
+	if updatedRows == 0 {
+		util.SendPreconditionFailed(c, util.ErrPreconditionFailed)
+		return
+	}
+
+	event.Publish(event.Event{
+		Type:    event.HouseUpdated,
+		OwnerID: updatedHouse.OwnerID,
+		Message: updatedHouse.Title + " is " + strings.ReplaceAll(status, "_", " "),
+		Data: map[string]interface{}{
+			"house_id": updatedHouse.ID,
+			"title":    updatedHouse.Title,
+			"status":   status,
+		},
+	})
+
+	if status == HouseStatusPublished {
+		AnnouncePublished(updatedHouse.ID, updatedHouse.OwnerID, updatedHouse.Title)
+	}
+
+	util.SendSuccess(c, gin.H{
+		"status": status,
+	})
+}
+
+func GetHouseList(c *gin.Context) {
+	listHouseQueryBuilder := filterHouse(houseListQueryBuilder(), houseFilterOf(c))
+
+	limitFilter, _ := strconv.Atoi(c.Query("limit"))
+	if limitFilter > 0 {
+		listHouseQueryBuilder = listHouseQueryBuilder.Limit(uint64(limitFilter))
+	}
+
+	offsetFilter, _ := strconv.Atoi(c.Query("offset"))
+	if offsetFilter > 0 {
+		listHouseQueryBuilder = listHouseQueryBuilder.Offset(uint64(offsetFilter))
+	}
+
+	if c.Query("sort") == "rating" {
+		listHouseQueryBuilder = listHouseQueryBuilder.OrderBy("COALESCE(rating.rating_average, 0) DESC", "COALESCE(rating.rating_count, 0) DESC")
+	}
+	listHouseQueryBuilder = listHouseQueryBuilder.OrderBy("created_at DESC")
+	houseList, err := listHouse(viewerOf(c), listHouseQueryBuilder)
+	if err != nil {
 		util.SendServerError(c, err)
 		return
 	}
@@ -303,13 +470,43 @@ func GetMyHouseList(c *gin.Context) {
 		return
 	}
 
-	myHouseList, err := db.Queries.ListMyHouse(context.TODO(), ownerID)
+	// deleted houses are listed apart, so they could be restored
+	myHouseList, err := db.Queries.ListMyHouse(context.TODO(), sqlc.ListMyHouseParams{
+		OwnerID: ownerID,
+		Deleted: c.Query("deleted") == "true",
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	houseIDs := make([]uuid.UUID, 0, len(myHouseList))
+	for _, v := range myHouseList {
+		houseIDs = append(houseIDs, v.ID)
+	}
+
+	rentalPlansOf, err := listRentalPlansOf(houseIDs)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	favoriteCountOf, err := listFavoriteCountsOf(houseIDs)
 	if err != nil {
 		util.SendServerError(c, err)
 		return
 	}
 
-	util.SendSuccess(c, myHouseList)
+	myHouseListWithPlans := make([]MyHouseListRow, 0, len(myHouseList))
+	for _, v := range myHouseList {
+		myHouseListWithPlans = append(myHouseListWithPlans, MyHouseListRow{
+			ListMyHouseRow: v,
+			RentalPlans:    rentalPlansOf(v.ID),
+			FavoriteCount:  favoriteCountOf(v.ID),
+		})
+	}
+
+	util.SendSuccess(c, myHouseListWithPlans)
 }
 
 func GetHouseDetail(c *gin.Context) {
@@ -326,76 +523,929 @@ func GetHouseDetail(c *gin.Context) {
 		return
 	}
 
-	util.SendSuccess(c, house)
-}
-
-func GetHouseCount(c *gin.Context) {
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	countHouseQueryBuilder := psql.Select("count(*)").From("homes")
+	if !IsVisible(c, house) {
+		util.SendNotFound(c, errors.New("house with the provided id is not exist"))
+		return
+	}
 
-	typeRentFilter := c.Query("type_rent")
-	if typeRentFilter != "" {
-		countHouseQueryBuilder = countHouseQueryBuilder.Where(sq.Eq{
-			"type_rent": typeRentFilter,
-		})
+	rentalPlans, err := db.Queries.ListHouseRentalPlan(context.TODO(), id)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	if rentalPlans == nil {
+		rentalPlans = make([]sqlc.HouseRentalPlan, 0)
 	}
 
-	minBedroomFilter, _ := strconv.Atoi(c.Query("bedrooms"))
-	if minBedroomFilter > 0 {
-		countHouseQueryBuilder = countHouseQueryBuilder.Where(sq.GtOrEq{
-			"bedrooms": minBedroomFilter,
-		})
+	rating, err := db.Queries.GetHouseRating(context.TODO(), id)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
 	}
 
-	minBathroomFilter, _ := strconv.Atoi(c.Query("bathrooms"))
-	if minBathroomFilter > 0 {
-		countHouseQueryBuilder = countHouseQueryBuilder.Where(sq.GtOrEq{
-			"bathrooms": minBathroomFilter,
-		})
+	contactVisible := canSeeContact(c, house)
+	if !contactVisible {
+		house.OwnerEmail = ""
+		house.OwnerPhoneNumber = ""
+		house.OwnerAddress = ""
 	}
 
-	maxPriceFilter, _ := strconv.Atoi(c.Query("price"))
-	if maxPriceFilter > 0 {
-		countHouseQueryBuilder = countHouseQueryBuilder.Where(sq.LtOrEq{
-			"price": maxPriceFilter,
-		})
+	viewer := viewerOf(c)
+	recordView(viewer, house)
+
+	isFavorite, err := listFavoritesOf(viewer, []uuid.UUID{id})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
 	}
 
-	provinceFilter, _ := strconv.Atoi(c.Query("province_id"))
-	if provinceFilter > 0 {
-		countHouseQueryBuilder = countHouseQueryBuilder.Where(sq.Eq{
-			"province_id": provinceFilter,
-		})
+	houseDetail := HouseDetail{
+		GetHouseByIdRow:     house,
+		RentalPlans:         rentalPlans,
+		RatingAverage:       rating.RatingAverage,
+		RatingCount:         rating.RatingCount,
+		OwnerContactVisible: contactVisible,
+		IsFavorite:          isFavorite(id),
 	}
 
-	cityFilter, _ := strconv.Atoi(c.Query("city_id"))
-	if cityFilter > 0 {
-		countHouseQueryBuilder = countHouseQueryBuilder.Where(sq.Eq{
-			"city_id": cityFilter,
-		})
+	if viewer != nil && viewer.UserID == house.OwnerID.String() {
+		favoriteCountOf, err := listFavoriteCountsOf([]uuid.UUID{id})
+		if err != nil {
+			util.SendServerError(c, err)
+			return
+		}
+
+		favoriteCount := favoriteCountOf(id)
+		houseDetail.FavoriteCount = &favoriteCount
 	}
 
-	amenitiesFilter := c.Query("amenities")
-	if amenitiesFilter != "" {
-		amenitiesFilters := strings.Split(amenitiesFilter, ",")
-		for _, v := range amenitiesFilters {
-			countHouseQueryBuilder = countHouseQueryBuilder.Where(sq.ILike{"amenities": "%" + v + "%"})
+	util.SetETag(c, house.UpdatedAt)
+	util.SendSuccess(c, houseDetail)
+}
+
+func GetOwnerProfile(c *gin.Context) {
+	owner, err := db.Queries.GetUserByUsername(context.TODO(), c.Param("username"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			util.SendNotFound(c, errors.New("owner with the provided username is not exist"))
+			return
 		}
+		util.SendServerError(c, err)
+		return
 	}
 
-	countHouseQuery, args, err := countHouseQueryBuilder.ToSql()
+	if owner.DeletedAt.Valid || owner.SuspendedAt.Valid {
+		util.SendNotFound(c, errors.New("owner with the provided username is not exist"))
+		return
+	}
+
+	isOwner, err := db.Queries.HasUserRole(context.TODO(), sqlc.HasUserRoleParams{
+		UserID:   owner.ID,
+		RoleName: "owner",
+	})
 	if err != nil {
 		util.SendServerError(c, err)
 		return
 	}
+	if !isOwner {
+		util.SendNotFound(c, errors.New("owner with the provided username is not exist"))
+		return
+	}
 
-	row := db.DB.QueryRowContext(context.TODO(), countHouseQuery, args...)
-	var houseCount int64
-	err = row.Scan(&houseCount)
+	houseList, err := listHouse(viewerOf(c), houseListQueryBuilder().Where(sq.Eq{"owner_id": owner.ID}).OrderBy("created_at DESC"))
 	if err != nil {
 		util.SendServerError(c, err)
 		return
 	}
 
-	util.SendSuccess(c, houseCount)
+	rating, err := db.Queries.GetOwnerRating(context.TODO(), owner.ID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	response, err := db.Queries.GetOwnerResponseRate(context.TODO(), owner.ID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	// the response rate is left empty until someone has actually contacted the owner
+	var responseRate *float64
+	if response.ConversationCount > 0 {
+		rate := float64(response.RepliedCount) / float64(response.ConversationCount)
+		responseRate = &rate
+	}
+
+	util.SendSuccess(c, OwnerProfile{
+		Fullname:      owner.Fullname,
+		Username:      owner.Username,
+		Avatar:        owner.Avatar,
+		JoinedAt:      owner.CreatedAt,
+		ListingCount:  len(houseList),
+		RatingAverage: rating.RatingAverage,
+		RatingCount:   rating.RatingCount,
+		ResponseRate:  responseRate,
+		Houses:        houseList,
+	})
+}
+
+func GetHouseCount(c *gin.Context) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	countHouseQueryBuilder := filterHouse(psql.Select("count(*)").From("homes").LeftJoin(houseRatingJoin).Where(sq.Eq{"status": HouseStatusPublished}), houseFilterOf(c))
+
+	countHouseQuery, args, err := countHouseQueryBuilder.ToSql()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	row := db.DB.QueryRowContext(context.TODO(), countHouseQuery, args...)
+	var houseCount int64
+	err = row.Scan(&houseCount)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, houseCount)
+}
+
+// ListHousePriceRule return the price rules of a house
+func ListHousePriceRule(c *gin.Context) {
+	houseID := c.Param("id")
+	id, err := uuid.Parse(houseID)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	house, err := db.Queries.GetHouseById(context.TODO(), id)
+	if err != nil || !IsVisible(c, house) {
+		util.SendNotFound(c, errors.New("house with the provided id is not exist"))
+		return
+	}
+
+	priceRules, err := db.Queries.ListHousePriceRule(context.TODO(), id)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	priceRuleList := make([]HousePriceRuleRow, 0, len(priceRules))
+	for _, v := range priceRules {
+		priceRuleList = append(priceRuleList, priceRuleRowOf(v))
+	}
+
+	util.SendSuccess(c, priceRuleList)
+}
+
+// CreateHousePriceRule add a rule overriding the nightly price of a daily rental house
+// on a date range and/or on some weekdays
+func CreateHousePriceRule(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	houseID := c.Param("id")
+	id, err := uuid.Parse(houseID)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	var req HousePriceRuleCreateRequest
+	err = c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	house, err := db.Queries.GetHouseById(context.TODO(), id)
+	if err != nil {
+		util.SendBadRequest(c, errors.New("house with the provided id is not exist"))
+		return
+	}
+
+	if userID != house.OwnerID.String() {
+		util.SendBadRequest(c, errors.New("you are not own this house, you could not change it's price"))
+		return
+	}
+
+	_, err = db.Queries.GetHouseRentalPlanByUnit(context.TODO(), sqlc.GetHouseRentalPlanByUnitParams{
+		HouseID: id,
+		Unit:    "day",
+	})
+	if err != nil {
+		util.SendBadRequest(c, errors.New("price rules are only available for house with a daily rental plan"))
+		return
+	}
+
+	weekdays, err := pricing.ParseWeekdays(req.Weekdays)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	if req.StartDate.IsZero() != req.EndDate.IsZero() {
+		util.SendBadRequest(c, errors.New("start_date and end_date should be provided together"))
+		return
+	}
+
+	if req.EndDate.Before(req.StartDate) {
+		util.SendBadRequest(c, errors.New("end_date should not be before start_date"))
+		return
+	}
+
+	if req.StartDate.IsZero() && len(weekdays) == 0 {
+		util.SendBadRequest(c, errors.New("price rule should have a date range or weekdays"))
+		return
+	}
+
+	newPriceRule, err := db.Queries.CreateHousePriceRule(context.TODO(), sqlc.CreateHousePriceRuleParams{
+		ID:        uuid.New(),
+		HouseID:   id,
+		Name:      req.Name,
+		StartDate: sql.NullTime{Time: req.StartDate, Valid: !req.StartDate.IsZero()},
+		EndDate:   sql.NullTime{Time: req.EndDate, Valid: !req.EndDate.IsZero()},
+		Weekdays:  req.Weekdays,
+		Price:     req.Price,
+		MinStay:   int32(req.MinStay),
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, priceRuleRowOf(newPriceRule))
+}
+
+func DeleteHousePriceRule(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	houseID := c.Param("id")
+	id, err := uuid.Parse(houseID)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	ruleID, err := uuid.Parse(c.Param("rule_id"))
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	house, err := db.Queries.GetHouseById(context.TODO(), id)
+	if err != nil {
+		util.SendBadRequest(c, errors.New("house with the provided id is not exist"))
+		return
+	}
+
+	if userID != house.OwnerID.String() {
+		util.SendBadRequest(c, errors.New("you are not own this house, you could not change it's price"))
+		return
+	}
+
+	priceRule, err := db.Queries.GetHousePriceRuleById(context.TODO(), ruleID)
+	if err != nil || priceRule.HouseID != id {
+		util.SendNotFound(c, errors.New("price rule with the provided id is not exist"))
+		return
+	}
+
+	err = db.Queries.DeleteHousePriceRule(context.TODO(), ruleID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, nil)
+}
+
+// GetHouseQuote return the itemised price of renting a house without booking it
+func GetHouseQuote(c *gin.Context) {
+	houseID := c.Param("id")
+	id, err := uuid.Parse(houseID)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	var req HouseQuoteRequest
+	err = c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	house, err := db.Queries.GetHouseById(context.TODO(), id)
+	if err != nil || !IsVisible(c, house) {
+		util.SendNotFound(c, errors.New("house with the provided id is not exist"))
+		return
+	}
+
+	rates, err := pricing.LoadRates(house, req.RentalPlanID)
+	if err != nil {
+		if errors.Is(err, pricing.ErrRentalPlanNotFound) {
+			util.SendBadRequest(c, err)
+			return
+		}
+
+		util.SendServerError(c, err)
+		return
+	}
+
+	quote, err := pricing.Calculate(rates, req.CheckIn, req.TimeRent)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	util.SendSuccess(c, quote)
+}
+
+// ListHouseRentalPlan return the rental plans offered by a house
+func ListHouseRentalPlan(c *gin.Context) {
+	houseID := c.Param("id")
+	id, err := uuid.Parse(houseID)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	house, err := db.Queries.GetHouseById(context.TODO(), id)
+	if err != nil || !IsVisible(c, house) {
+		util.SendNotFound(c, errors.New("house with the provided id is not exist"))
+		return
+	}
+
+	rentalPlans, err := db.Queries.ListHouseRentalPlan(context.TODO(), id)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	if rentalPlans == nil {
+		rentalPlans = make([]sqlc.HouseRentalPlan, 0)
+	}
+
+	util.SendSuccess(c, rentalPlans)
+}
+
+// SaveHouseRentalPlan add a rental plan to a house, or replace the plan of the same unit
+func SaveHouseRentalPlan(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	houseID := c.Param("id")
+	id, err := uuid.Parse(houseID)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	var req HouseRentalPlanRequest
+	err = c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	house, err := db.Queries.GetHouseById(context.TODO(), id)
+	if err != nil {
+		util.SendBadRequest(c, errors.New("house with the provided id is not exist"))
+		return
+	}
+
+	if userID != house.OwnerID.String() {
+		util.SendBadRequest(c, errors.New("you are not own this house, you could not change it's rental plans"))
+		return
+	}
+
+	err = util.CheckIfMatch(c, house.UpdatedAt)
+	if err != nil {
+		util.SendPreconditionFailed(c, err)
+		return
+	}
+
+	maxDuration := pricing.MaxTimeRent(req.Unit)
+	if req.MinDuration == 0 {
+		req.MinDuration = 1
+	}
+	if req.MaxDuration == 0 {
+		req.MaxDuration = maxDuration
+	}
+	if req.MinDuration > req.MaxDuration || req.MaxDuration > maxDuration {
+		util.SendBadRequest(c, fmt.Errorf("duration of a %s rental plan should be between 1 and %d", req.Unit, maxDuration))
+		return
+	}
+
+	tx, err := db.DB.BeginTx(context.TODO(), nil)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := db.Queries.WithTx(tx)
+
+	rentalPlan, err := qtx.UpsertHouseRentalPlan(context.TODO(), sqlc.UpsertHouseRentalPlanParams{
+		ID:          uuid.New(),
+		HouseID:     id,
+		Unit:        req.Unit,
+		Price:       req.Price,
+		MinDuration: int32(req.MinDuration),
+		MaxDuration: int32(req.MaxDuration),
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	// the house's type of rent & price mirror it's primary plan, which is only changed when the house
+	// is not changed since it's read above, so a plan which isn't primary anymore is never mirrored
+	if req.Unit == house.TypeRent {
+		var updatedRows int64
+		updatedRows, err = qtx.UpdateHousePrimaryRentalPlan(context.TODO(), sqlc.UpdateHousePrimaryRentalPlanParams{
+			ID:                id,
+			TypeRent:          req.Unit,
+			Price:             req.Price,
+			UpdatedAt:         time.Now(),
+			ExpectedUpdatedAt: house.UpdatedAt,
+		})
+		if err != nil {
+			util.SendServerError(c, err)
+			return
+		}
+		if updatedRows == 0 {
+			util.SendPreconditionFailed(c, util.ErrPreconditionFailed)
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, rentalPlan)
+}
+
+func DeleteHouseRentalPlan(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	houseID := c.Param("id")
+	id, err := uuid.Parse(houseID)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	planID, err := uuid.Parse(c.Param("plan_id"))
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	house, err := db.Queries.GetHouseById(context.TODO(), id)
+	if err != nil {
+		util.SendBadRequest(c, errors.New("house with the provided id is not exist"))
+		return
+	}
+
+	if userID != house.OwnerID.String() {
+		util.SendBadRequest(c, errors.New("you are not own this house, you could not change it's rental plans"))
+		return
+	}
+
+	rentalPlan, err := db.Queries.GetHouseRentalPlanById(context.TODO(), planID)
+	if err != nil || rentalPlan.HouseID != id {
+		util.SendNotFound(c, errors.New("rental plan with the provided id is not exist"))
+		return
+	}
+
+	if rentalPlan.Unit == house.TypeRent {
+		util.SendBadRequest(c, errors.New("could not delete the primary rental plan, change the type_rent of the house first"))
+		return
+	}
+
+	err = db.Queries.DeleteHouseRentalPlan(context.TODO(), planID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, nil)
+}
+
+// houseListQueryBuilder select the published houses along with their rating, the way listHouse scan them
+func houseListQueryBuilder() sq.SelectBuilder {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	return psql.Select("id", "title", "featured_image", "bedrooms", "bathrooms", "type_rent", "price", "province_id", "city_id", "description", "amenities", "area", "created_at", "updated_at", "COALESCE(rating.rating_average, 0)", "COALESCE(rating.rating_count, 0)").From("homes").LeftJoin(houseRatingJoin).Where(sq.Eq{"status": HouseStatusPublished})
+}
+
+// listHouse run a query built from houseListQueryBuilder, along with the rental plans of each house
+// and whether the viewer has it in it's favourites
+func listHouse(viewer *util.UserPayload, listHouseQueryBuilder sq.SelectBuilder) ([]HouseListRow, error) {
+	listHouseQuery, args, err := listHouseQueryBuilder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.DB.QueryContext(context.TODO(), listHouseQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	houseList := make([]HouseListRow, 0)
+	for rows.Next() {
+		var i HouseListRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.FeaturedImage,
+			&i.Bedrooms,
+			&i.Bathrooms,
+			&i.TypeRent,
+			&i.Price,
+			&i.ProvinceID,
+			&i.CityID,
+			&i.Description,
+			&i.Amenities,
+			&i.Area,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RatingAverage,
+			&i.RatingCount,
+		); err != nil {
+			return nil, err
+		}
+		houseList = append(houseList, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	houseIDs := make([]uuid.UUID, 0, len(houseList))
+	for _, v := range houseList {
+		houseIDs = append(houseIDs, v.ID)
+	}
+
+	rentalPlansOf, err := listRentalPlansOf(houseIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	isFavorite, err := listFavoritesOf(viewer, houseIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range houseList {
+		houseList[i].RentalPlans = rentalPlansOf(houseList[i].ID)
+		houseList[i].IsFavorite = isFavorite(houseList[i].ID)
+	}
+
+	return houseList, nil
+}
+
+// IsVisible checks if the viewer could see the house, a house which isn't published
+// is only visible to it's owner & admins
+func IsVisible(c *gin.Context, house sqlc.GetHouseByIdRow) bool {
+	if house.Status == HouseStatusPublished {
+		return true
+	}
+
+	viewer := viewerOf(c)
+	if viewer == nil {
+		return false
+	}
+	if viewer.UserID == house.OwnerID.String() || viewer.HasPermission(user.PermissionHouseModerate) {
+		return true
+	}
+
+	// a tenant could still look up a house they booked, even once it's unlisted or deleted
+	tenantID, _ := uuid.Parse(viewer.UserID)
+	booked, err := db.Queries.HasTenantBookedHouse(context.TODO(), sqlc.HasTenantBookedHouseParams{
+		HouseID:  house.ID,
+		TenantID: tenantID,
+	})
+	return err == nil && booked
+}
+
+// checkNotDeleted return an error for a deleted house, it has to be restored before it's updated
+func checkNotDeleted(houseID uuid.UUID) error {
+	deletedAt, err := db.Queries.GetHouseDeletedAt(context.TODO(), houseID)
+	if err != nil {
+		return errors.New("house with the provided id is not exist")
+	}
+	if deletedAt.Valid {
+		return errors.New("house is deleted, restore it before updating it")
+	}
+
+	return nil
+}
+
+// publishStatusOf return the status a house gets when it's owner publish it, it waits for an admin
+// approval when it's required or when the house was taken down by an admin before
+func publishStatusOf(moderationNote string) string {
+	if config.HouseApprovalRequired || moderationNote != "" {
+		return HouseStatusPendingReview
+	}
+
+	return HouseStatusPublished
+}
+
+// canSeeContact checks if the viewer could see the owner's contact details, depending on
+// the owner's preference it's either any logged in tenant or only the tenants who booked the house
+func canSeeContact(c *gin.Context, house sqlc.GetHouseByIdRow) bool {
+	viewer := viewerOf(c)
+	if viewer == nil {
+		return false
+	}
+	if viewer.UserID == house.OwnerID.String() || viewer.HasPermission(user.PermissionHouseModerate) {
+		return true
+	}
+	if !viewer.HasRole("tenant") {
+		return false
+	}
+	if house.OwnerContactVisibility == user.ContactVisibilityTenants {
+		return true
+	}
+
+	tenantID, _ := uuid.Parse(viewer.UserID)
+	booked, err := db.Queries.HasTenantBookedHouse(context.TODO(), sqlc.HasTenantBookedHouseParams{
+		HouseID:  house.ID,
+		TenantID: tenantID,
+	})
+	return err == nil && booked
+}
+
+// recordView count a view of a published house for the stats of it's owner, the owner's own views
+// aren't counted & a failure is only logged, it should never fail the house detail
+func recordView(viewer *util.UserPayload, house sqlc.GetHouseByIdRow) {
+	if house.Status != HouseStatusPublished || (viewer != nil && viewer.UserID == house.OwnerID.String()) {
+		return
+	}
+
+	err := db.Queries.RecordHouseView(context.TODO(), sqlc.RecordHouseViewParams{
+		HouseID:  house.ID,
+		ViewedOn: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Printf("couldn't record view of house %s: %v", house.ID, err)
+	}
+}
+
+// viewerOf return the logged in user viewing a public endpoint, as set by user.OptionalAuth,
+// or nil for an anonymous visitor
+func viewerOf(c *gin.Context) *util.UserPayload {
+	payload, _ := c.Get("user")
+	viewer, _ := payload.(*util.UserPayload)
+	return viewer
+}
+
+// houseRatingJoin join the average rating & the number of reviews of each house as rating
+const houseRatingJoin = "(SELECT house_id, AVG(rating)::float8 AS rating_average, COUNT(*) AS rating_count FROM reviews GROUP BY house_id) AS rating ON rating.house_id = homes.id"
+
+// houseFilterOf read the filter of the house list from the query params, an invalid param is ignored
+func houseFilterOf(c *gin.Context) HouseFilter {
+	var filter HouseFilter
+	filter.Bedrooms, _ = strconv.Atoi(c.Query("bedrooms"))
+	filter.Bathrooms, _ = strconv.Atoi(c.Query("bathrooms"))
+	filter.TypeRent = c.Query("type_rent")
+	filter.Price, _ = strconv.Atoi(c.Query("price"))
+	filter.ProvinceID, _ = strconv.Atoi(c.Query("province_id"))
+	filter.CityID, _ = strconv.Atoi(c.Query("city_id"))
+	filter.Amenities = c.Query("amenities")
+	filter.Rating, _ = strconv.ParseFloat(c.Query("rating"), 64)
+
+	return filter
+}
+
+// filterHouse narrow a query of the homes joined with houseRatingJoin down to the houses matching the filter
+func filterHouse(houseQueryBuilder sq.SelectBuilder, filter HouseFilter) sq.SelectBuilder {
+	if filter.Bedrooms > 0 {
+		houseQueryBuilder = houseQueryBuilder.Where(sq.GtOrEq{
+			"bedrooms": filter.Bedrooms,
+		})
+	}
+
+	if filter.Bathrooms > 0 {
+		houseQueryBuilder = houseQueryBuilder.Where(sq.GtOrEq{
+			"bathrooms": filter.Bathrooms,
+		})
+	}
+
+	if filter.TypeRent != "" || filter.Price > 0 {
+		houseQueryBuilder = houseQueryBuilder.Where(rentalPlanFilter(filter.TypeRent, filter.Price))
+	}
+
+	if filter.ProvinceID > 0 {
+		houseQueryBuilder = houseQueryBuilder.Where(sq.Eq{
+			"province_id": filter.ProvinceID,
+		})
+	}
+
+	if filter.CityID > 0 {
+		houseQueryBuilder = houseQueryBuilder.Where(sq.Eq{
+			"city_id": filter.CityID,
+		})
+	}
+
+	if filter.Amenities != "" {
+		amenitiesFilters := strings.Split(filter.Amenities, ",")
+		for _, v := range amenitiesFilters {
+			houseQueryBuilder = houseQueryBuilder.Where(sq.ILike{"amenities": "%" + v + "%"})
+		}
+	}
+
+	if filter.Rating > 0 {
+		houseQueryBuilder = houseQueryBuilder.Where("COALESCE(rating.rating_average, 0) >= ?", filter.Rating)
+	}
+
+	return houseQueryBuilder
+}
+
+// rentalPlanFilter match houses offering a rental plan of the type of rent and/or at most the price
+func rentalPlanFilter(typeRent string, maxPrice int) sq.Sqlizer {
+	planQueryBuilder := sq.Select("1").From("house_rental_plans AS plan").Where("plan.house_id = homes.id")
+	if typeRent != "" {
+		planQueryBuilder = planQueryBuilder.Where(sq.Eq{"plan.unit": typeRent})
+	}
+	if maxPrice > 0 {
+		planQueryBuilder = planQueryBuilder.Where(sq.LtOrEq{"plan.price": maxPrice})
+	}
+
+	planQuery, args, _ := planQueryBuilder.ToSql()
+	return sq.Expr("EXISTS ("+planQuery+")", args...)
+}
+
+// listRentalPlansOf return a lookup of the rental plans of the houses
+func listRentalPlansOf(houseIDs []uuid.UUID) (func(uuid.UUID) []sqlc.HouseRentalPlan, error) {
+	rentalPlans, err := db.Queries.ListHouseRentalPlanByHouseIds(context.TODO(), houseIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rentalPlansByHouse := make(map[uuid.UUID][]sqlc.HouseRentalPlan)
+	for _, v := range rentalPlans {
+		rentalPlansByHouse[v.HouseID] = append(rentalPlansByHouse[v.HouseID], v)
+	}
+
+	return func(houseID uuid.UUID) []sqlc.HouseRentalPlan {
+		if plans, ok := rentalPlansByHouse[houseID]; ok {
+			return plans
+		}
+		return make([]sqlc.HouseRentalPlan, 0)
+	}, nil
+}
+
+// saveHouseUpdate update a house along with it's primary rental plan, util.ErrPreconditionFailed is
+// returned when the house is changed since the expected version
+func saveHouseUpdate(params sqlc.UpdateHouseParams) (sqlc.Home, error) {
+	tx, err := db.DB.BeginTx(context.TODO(), nil)
+	if err != nil {
+		return sqlc.Home{}, err
+	}
+	defer tx.Rollback()
+	qtx := db.Queries.WithTx(tx)
+
+	updatedHouse, err := qtx.UpdateHouse(context.TODO(), params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return sqlc.Home{}, util.ErrPreconditionFailed
+		}
+		return sqlc.Home{}, err
+	}
+
+	err = savePrimaryRentalPlan(qtx, updatedHouse.ID, updatedHouse.TypeRent, updatedHouse.Price)
+	if err != nil {
+		return sqlc.Home{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return sqlc.Home{}, err
+	}
+
+	return updatedHouse, nil
+}
+
+// houseUpdateRequestOf fill an update request with the current house, for the update to be applied onto
+func houseUpdateRequestOf(house sqlc.GetHouseByIdRow) HouseUpdateRequest {
+	return HouseUpdateRequest{
+		Title:           house.Title,
+		Bedrooms:        int(house.Bedrooms),
+		Bathrooms:       int(house.Bathrooms),
+		TypeRent:        house.TypeRent,
+		Price:           house.Price,
+		ProvinceID:      int(house.ProvinceID),
+		CityID:          int(house.CityID),
+		Description:     house.Description,
+		Amenities:       house.Amenities,
+		Area:            int(house.Area),
+		SecurityDeposit: house.SecurityDeposit,
+		CleaningFee:     house.CleaningFee,
+		ServiceFee:      house.ServiceFee,
+		WeeklyDiscount:  house.WeeklyDiscount,
+		MonthlyDiscount: house.MonthlyDiscount,
+		TaxRate:         house.TaxRate,
+	}
+}
+
+func destroyFeaturedImage(featuredImageURL string) {
+	err := media.DestroyMedia(featuredImageURL)
+	if err != nil {
+		log.Printf("couldn't destroy featured image %s: %v", featuredImageURL, err)
+	}
+}
+
+// createHouse save a new house along with it's primary rental plan, a house is saved as a draft
+// unless it's owner publish it right away
+func createHouse(q *sqlc.Queries, ownerID uuid.UUID, req HouseCreateRequest, featuredImageURL string) (sqlc.Home, error) {
+	status := HouseStatusDraft
+	if req.Status == HouseStatusPublished {
+		status = publishStatusOf("")
+	}
+
+	newHouse, err := q.CreateHouse(context.TODO(), sqlc.CreateHouseParams{
+		ID:              uuid.New(),
+		OwnerID:         ownerID,
+		Title:           req.Title,
+		FeaturedImage:   featuredImageURL,
+		Bedrooms:        int32(req.Bedrooms),
+		Bathrooms:       int32(req.Bathrooms),
+		TypeRent:        req.TypeRent,
+		Price:           req.Price,
+		ProvinceID:      int32(req.ProvinceID),
+		CityID:          int32(req.CityID),
+		Amenities:       req.Amenities,
+		Description:     req.Description,
+		Area:            int32(req.Area),
+		SecurityDeposit: req.SecurityDeposit,
+		CleaningFee:     req.CleaningFee,
+		ServiceFee:      req.ServiceFee,
+		WeeklyDiscount:  req.WeeklyDiscount,
+		MonthlyDiscount: req.MonthlyDiscount,
+		TaxRate:         req.TaxRate,
+		Status:          status,
+	})
+	if err != nil {
+		return sqlc.Home{}, err
+	}
+
+	err = savePrimaryRentalPlan(q, newHouse.ID, newHouse.TypeRent, newHouse.Price)
+	if err != nil {
+		return sqlc.Home{}, err
+	}
+
+	return newHouse, nil
+}
+
+// savePrimaryRentalPlan keep the rental plan mirrored by the house's type of rent & price in sync
+func savePrimaryRentalPlan(q *sqlc.Queries, houseID uuid.UUID, typeRent string, price int64) error {
+	minDuration, maxDuration := int32(1), int32(pricing.MaxTimeRent(typeRent))
+	primaryPlan, err := q.GetHouseRentalPlanByUnit(context.TODO(), sqlc.GetHouseRentalPlanByUnitParams{
+		HouseID: houseID,
+		Unit:    typeRent,
+	})
+	if err == nil {
+		minDuration, maxDuration = primaryPlan.MinDuration, primaryPlan.MaxDuration
+	}
+
+	_, err = q.UpsertHouseRentalPlan(context.TODO(), sqlc.UpsertHouseRentalPlanParams{
+		ID:          uuid.New(),
+		HouseID:     houseID,
+		Unit:        typeRent,
+		Price:       price,
+		MinDuration: minDuration,
+		MaxDuration: maxDuration,
+	})
+	return err
+}
+
+func priceRuleRowOf(priceRule sqlc.HousePriceRule) HousePriceRuleRow {
+	row := HousePriceRuleRow{
+		ID:        priceRule.ID,
+		HouseID:   priceRule.HouseID,
+		Name:      priceRule.Name,
+		Weekdays:  priceRule.Weekdays,
+		Price:     priceRule.Price,
+		MinStay:   priceRule.MinStay,
+		CreatedAt: priceRule.CreatedAt,
+	}
+	if priceRule.StartDate.Valid && priceRule.EndDate.Valid {
+		row.StartDate = priceRule.StartDate.Time.Format("2006-01-02")
+		row.EndDate = priceRule.EndDate.Time.Format("2006-01-02")
+	}
+
+	return row
 }
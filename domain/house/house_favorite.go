@@ -0,0 +1,146 @@
+package house
+
+import (
+	"context"
+	"errors"
+
+	db "gubuk-service/db"
+	sqlc "gubuk-service/db/sqlc"
+	"gubuk-service/util"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func AddFavoriteHouse(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	tenantID, err := uuid.Parse(userID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	houseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	house, err := db.Queries.GetHouseById(context.TODO(), houseID)
+	if err != nil || !IsVisible(c, house) {
+		util.SendNotFound(c, errors.New("house with the provided id is not exist"))
+		return
+	}
+
+	err = db.Queries.AddFavorite(context.TODO(), sqlc.AddFavoriteParams{
+		UserID:  tenantID,
+		HouseID: houseID,
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, nil)
+}
+
+func DeleteFavoriteHouse(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	tenantID, err := uuid.Parse(userID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	houseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	err = db.Queries.DeleteFavorite(context.TODO(), sqlc.DeleteFavoriteParams{
+		UserID:  tenantID,
+		HouseID: houseID,
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, nil)
+}
+
+func GetFavoriteHouseList(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	tenantID, err := uuid.Parse(userID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	// a favourite house which is no longer published is left out, until it's published again
+	houseList, err := listHouse(userPayload, houseListQueryBuilder().Where(sq.Expr("id IN (SELECT house_id FROM favorites WHERE user_id = ?)", tenantID)).OrderBy("created_at DESC"))
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, houseList)
+}
+
+// listFavoritesOf checks which of the houses the viewer has in it's favourites, an anonymous viewer has none
+func listFavoritesOf(viewer *util.UserPayload, houseIDs []uuid.UUID) (func(uuid.UUID) bool, error) {
+	favoriteHouses := make(map[uuid.UUID]bool)
+	isFavorite := func(houseID uuid.UUID) bool {
+		return favoriteHouses[houseID]
+	}
+	if viewer == nil || len(houseIDs) == 0 {
+		return isFavorite, nil
+	}
+
+	userID, err := uuid.Parse(viewer.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	favoriteHouseIDs, err := db.Queries.ListFavoriteHouseIdByHouseIds(context.TODO(), sqlc.ListFavoriteHouseIdByHouseIdsParams{
+		UserID:   userID,
+		HouseIds: houseIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range favoriteHouseIDs {
+		favoriteHouses[v] = true
+	}
+
+	return isFavorite, nil
+}
+
+// listFavoriteCountsOf count how many users have each of the houses in their favourites
+func listFavoriteCountsOf(houseIDs []uuid.UUID) (func(uuid.UUID) int64, error) {
+	favoriteCounts, err := db.Queries.CountFavoriteByHouseIds(context.TODO(), houseIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	favoriteCountByHouse := make(map[uuid.UUID]int64)
+	for _, v := range favoriteCounts {
+		favoriteCountByHouse[v.HouseID] = v.FavoriteCount
+	}
+
+	return func(houseID uuid.UUID) int64 {
+		return favoriteCountByHouse[houseID]
+	}, nil
+}
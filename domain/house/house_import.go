@@ -0,0 +1,696 @@
+package house
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	db "gubuk-service/db"
+	sqlc "gubuk-service/db/sqlc"
+	"gubuk-service/media"
+	"gubuk-service/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+)
+
+const (
+	importPollInterval = 10 * time.Second
+	importLease        = 10 * time.Minute
+	importBatchSize    = 5
+
+	maxImportRows        = 500
+	maxImportFileSize    = 5 << 20
+	maxImportArchiveSize = 50 << 20
+)
+
+// ImportHouse queue a bulk import of the listings in a csv or json file, each listing has the same fields
+// as the form of CreateHouse where it's featured_image is either an image url or the name of an image
+// inside a zip, which is either sent as images or sent as the file itself along with the listings
+func ImportHouse(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	var req HouseImportCreateRequest
+	err = c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	if req.Mode == "" {
+		req.Mode = HouseImportAllOrNothing
+	}
+
+	listingFile, err := c.FormFile("file")
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	var rows []map[string]string
+	var images []byte
+	if strings.EqualFold(filepath.Ext(listingFile.Filename), ".zip") {
+		images, err = readImportArchive(listingFile)
+		if err != nil {
+			util.SendBadRequest(c, err)
+			return
+		}
+
+		rows, err = readImportRowsFromArchive(images)
+	} else {
+		rows, err = readImportRowsFromFile(listingFile)
+	}
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	if len(rows) == 0 {
+		util.SendBadRequest(c, errors.New("the file has no listing to import"))
+		return
+	}
+	if len(rows) > maxImportRows {
+		util.SendBadRequest(c, fmt.Errorf("the file should not have more than %d listings", maxImportRows))
+		return
+	}
+
+	imageArchive, err := c.FormFile("images")
+	if err == nil {
+		if images != nil {
+			util.SendBadRequest(c, errors.New("images should not be sent along with a zip file"))
+			return
+		}
+
+		images, err = readImportArchive(imageArchive)
+		if err != nil {
+			util.SendBadRequest(c, err)
+			return
+		}
+	}
+
+	encodedRows, err := json.Marshal(rows)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	newImport, err := db.Queries.CreateHouseImport(context.TODO(), sqlc.CreateHouseImportParams{
+		ID:        uuid.New(),
+		OwnerID:   ownerID,
+		Mode:      req.Mode,
+		Rows:      encodedRows,
+		Images:    images,
+		TotalRows: int32(len(rows)),
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, houseImportRowOf(newImport))
+}
+
+// GetHouseImport return the progress of a house import along with the rows it couldn't create so far
+func GetHouseImport(c *gin.Context) {
+	errNotExist := errors.New("house import with the provided id is not exist")
+
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.SendNotFound(c, errNotExist)
+		return
+	}
+
+	houseImport, err := db.Queries.GetHouseImportById(context.TODO(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			util.SendNotFound(c, errNotExist)
+			return
+		}
+		util.SendServerError(c, err)
+		return
+	}
+
+	if houseImport.OwnerID.String() != userPayload.UserID {
+		util.SendNotFound(c, errNotExist)
+		return
+	}
+
+	util.SendSuccess(c, houseImportRowOf(houseImport))
+}
+
+// StartHouseImportWorker run the queued house imports in the background, an import which is cut short
+// (e.g. by a restart) is picked up again once it's lease runs out
+func StartHouseImportWorker() {
+	go func() {
+		ticker := time.NewTicker(importPollInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			importDue()
+		}
+	}()
+}
+
+func importDue() {
+	houseImports, err := db.Queries.ClaimDueHouseImport(context.TODO(), sqlc.ClaimDueHouseImportParams{
+		LeaseUntil: time.Now().Add(importLease),
+		BatchSize:  importBatchSize,
+	})
+	if err != nil {
+		log.Printf("couldn't claim house imports: %v", err)
+		return
+	}
+
+	for _, v := range houseImports {
+		runImport(v)
+	}
+}
+
+func runImport(houseImport sqlc.HouseImport) {
+	var rows []map[string]string
+	err := json.Unmarshal(houseImport.Rows, &rows)
+	if err != nil {
+		log.Printf("couldn't decode rows of house import %s: %v", houseImport.ID, err)
+		finishImport(houseImport.ID, HouseImportFailed, 0, 0)
+		return
+	}
+
+	var images *zip.Reader
+	if len(houseImport.Images) > 0 {
+		images, err = zip.NewReader(bytes.NewReader(houseImport.Images), int64(len(houseImport.Images)))
+		if err != nil {
+			log.Printf("couldn't open images of house import %s: %v", houseImport.ID, err)
+			finishImport(houseImport.ID, HouseImportFailed, 0, 0)
+			return
+		}
+	}
+
+	if houseImport.Mode == HouseImportPartial {
+		importPartially(houseImport, rows, images)
+	} else {
+		importAllOrNothing(houseImport, rows, images)
+	}
+}
+
+// importPartially create every valid row on it's own, the progress is saved along with each house
+// so an import which is picked up again continues after the last row it processed
+func importPartially(houseImport sqlc.HouseImport, rows []map[string]string, images *zip.Reader) {
+	createdRows := houseImport.CreatedRows
+	for i := int(houseImport.ProcessedRows); i < len(rows); i++ {
+		progress := sqlc.UpdateHouseImportProgressParams{
+			ProcessedRows: int32(i + 1),
+			CreatedRows:   createdRows,
+			NewErrors:     importErrorsOf(),
+			LeaseUntil:    time.Now().Add(importLease),
+			ID:            houseImport.ID,
+		}
+
+		newHouse, err := importRow(houseImport.OwnerID, rows[i], images, progress)
+		if err != nil {
+			progress.NewErrors = importErrorsOf(HouseImportError{Row: i + 1, Error: err.Error()})
+			err = db.Queries.UpdateHouseImportProgress(context.TODO(), progress)
+			if err != nil {
+				log.Printf("couldn't save progress of house import %s: %v", houseImport.ID, err)
+				return
+			}
+			continue
+		}
+
+		createdRows++
+		if newHouse.Status == HouseStatusPublished {
+			AnnouncePublished(newHouse.ID, newHouse.OwnerID, newHouse.Title)
+		}
+	}
+
+	finishImport(houseImport.ID, HouseImportCompleted, len(rows), int(createdRows))
+}
+
+// importRow create the house of a row & save the progress of it's import at once
+func importRow(ownerID uuid.UUID, row map[string]string, images *zip.Reader, progress sqlc.UpdateHouseImportProgressParams) (sqlc.Home, error) {
+	req, err := validateImportRow(row, images)
+	if err != nil {
+		return sqlc.Home{}, err
+	}
+
+	featuredImageURL, err := uploadImportImage(row["featured_image"], images)
+	if err != nil {
+		return sqlc.Home{}, err
+	}
+
+	tx, err := db.DB.BeginTx(context.TODO(), nil)
+	if err != nil {
+		destroyImportImages(featuredImageURL)
+		return sqlc.Home{}, err
+	}
+	defer tx.Rollback()
+	qtx := db.Queries.WithTx(tx)
+
+	newHouse, err := createHouse(qtx, ownerID, req, featuredImageURL)
+	if err != nil {
+		destroyImportImages(featuredImageURL)
+		return sqlc.Home{}, err
+	}
+
+	progress.CreatedRows++
+	err = qtx.UpdateHouseImportProgress(context.TODO(), progress)
+	if err != nil {
+		destroyImportImages(featuredImageURL)
+		return sqlc.Home{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		destroyImportImages(featuredImageURL)
+		return sqlc.Home{}, err
+	}
+
+	return newHouse, nil
+}
+
+// importAllOrNothing validate every row & upload every image before creating the houses in one
+// transaction, no house is created when any row fails. The uploaded images are saved along the way,
+// so an import which is picked up again reuses them instead of leaving them behind
+func importAllOrNothing(houseImport sqlc.HouseImport, rows []map[string]string, images *zip.Reader) {
+	reqs := make([]HouseCreateRequest, len(rows))
+	importErrors := make([]HouseImportError, 0)
+	for i, row := range rows {
+		req, err := validateImportRow(row, images)
+		if err != nil {
+			importErrors = append(importErrors, HouseImportError{Row: i + 1, Error: err.Error()})
+			continue
+		}
+		reqs[i] = req
+	}
+
+	if len(importErrors) > 0 {
+		finishImport(houseImport.ID, HouseImportFailed, len(rows), 0, importErrors...)
+		return
+	}
+
+	featuredImageURLs := make([]string, 0, len(rows))
+	err := json.Unmarshal(houseImport.UploadedImages, &featuredImageURLs)
+	if err != nil {
+		log.Printf("couldn't decode uploaded images of house import %s: %v", houseImport.ID, err)
+		featuredImageURLs = featuredImageURLs[:0]
+	}
+
+	// the processed rows count the uploaded images here, as it's the slow part of the import
+	for i := len(featuredImageURLs); i < len(rows); i++ {
+		featuredImageURL, err := uploadImportImage(rows[i]["featured_image"], images)
+		if err != nil {
+			destroyImportImages(featuredImageURLs...)
+			finishImport(houseImport.ID, HouseImportFailed, i+1, 0, HouseImportError{Row: i + 1, Error: err.Error()})
+			return
+		}
+		featuredImageURLs = append(featuredImageURLs, featuredImageURL)
+
+		uploadedImages, _ := json.Marshal(featuredImageURLs)
+		err = db.Queries.SaveHouseImportUploadedImages(context.TODO(), sqlc.SaveHouseImportUploadedImagesParams{
+			ProcessedRows:  int32(i + 1),
+			UploadedImages: uploadedImages,
+			LeaseUntil:     time.Now().Add(importLease),
+			ID:             houseImport.ID,
+		})
+		if err != nil {
+			log.Printf("couldn't save progress of house import %s: %v", houseImport.ID, err)
+		}
+	}
+
+	newHouses, err := createImportedHouses(houseImport, reqs, featuredImageURLs)
+	if err != nil {
+		destroyImportImages(featuredImageURLs...)
+		finishImport(houseImport.ID, HouseImportFailed, len(rows), 0, importErrorsFrom(err)...)
+		return
+	}
+
+	for _, v := range newHouses {
+		if v.Status == HouseStatusPublished {
+			AnnouncePublished(v.ID, v.OwnerID, v.Title)
+		}
+	}
+}
+
+// createImportedHouses create the houses of an all or nothing import & finish it in one transaction
+func createImportedHouses(houseImport sqlc.HouseImport, reqs []HouseCreateRequest, featuredImageURLs []string) ([]sqlc.Home, error) {
+	tx, err := db.DB.BeginTx(context.TODO(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := db.Queries.WithTx(tx)
+
+	newHouses := make([]sqlc.Home, 0, len(reqs))
+	for i, req := range reqs {
+		newHouse, err := createHouse(qtx, houseImport.OwnerID, req, featuredImageURLs[i])
+		if err != nil {
+			return nil, importRowError{row: i + 1, err: err}
+		}
+		newHouses = append(newHouses, newHouse)
+	}
+
+	finishedAt := time.Now()
+	err = qtx.FinishHouseImport(context.TODO(), sqlc.FinishHouseImportParams{
+		Status:        HouseImportCompleted,
+		ProcessedRows: int32(len(reqs)),
+		CreatedRows:   int32(len(reqs)),
+		NewErrors:     importErrorsOf(),
+		FinishedAt:    sql.NullTime{Time: finishedAt, Valid: true},
+		ID:            houseImport.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return newHouses, nil
+}
+
+// importRowError is an error of a row, as opposed to an error of the whole import
+type importRowError struct {
+	row int
+	err error
+}
+
+func (e importRowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.row, e.err)
+}
+
+// importErrorsFrom report an error of an all or nothing import, an error which isn't about a row
+// is reported as an error of the whole import (row 0)
+func importErrorsFrom(err error) []HouseImportError {
+	var rowError importRowError
+	if errors.As(err, &rowError) {
+		return []HouseImportError{{Row: rowError.row, Error: rowError.err.Error()}}
+	}
+
+	return []HouseImportError{{Row: 0, Error: err.Error()}}
+}
+
+func finishImport(id uuid.UUID, status string, processedRows int, createdRows int, importErrors ...HouseImportError) {
+	err := db.Queries.FinishHouseImport(context.TODO(), sqlc.FinishHouseImportParams{
+		Status:        status,
+		ProcessedRows: int32(processedRows),
+		CreatedRows:   int32(createdRows),
+		NewErrors:     importErrorsOf(importErrors...),
+		FinishedAt:    sql.NullTime{Time: time.Now(), Valid: true},
+		ID:            id,
+	})
+	if err != nil {
+		log.Printf("couldn't finish house import %s: %v", id, err)
+	}
+}
+
+func importErrorsOf(importErrors ...HouseImportError) json.RawMessage {
+	if len(importErrors) == 0 {
+		return json.RawMessage("[]")
+	}
+
+	encodedErrors, _ := json.Marshal(importErrors)
+	return encodedErrors
+}
+
+// validateImportRow check a row with the same rules as the form of CreateHouse, along with it's featured image
+func validateImportRow(row map[string]string, images *zip.Reader) (HouseCreateRequest, error) {
+	values := make(url.Values)
+	for k, v := range row {
+		values.Set(k, v)
+	}
+
+	var req HouseCreateRequest
+	err := binding.Query.Bind(&http.Request{URL: &url.URL{RawQuery: values.Encode()}}, &req)
+	if err != nil {
+		return HouseCreateRequest{}, err
+	}
+
+	featuredImage := row["featured_image"]
+	if featuredImage == "" {
+		return HouseCreateRequest{}, errors.New("featured_image is required")
+	}
+
+	if isRemoteImage(featuredImage) {
+		return req, nil
+	}
+
+	image, err := openImportImage(featuredImage, images)
+	if err != nil {
+		return HouseCreateRequest{}, err
+	}
+	defer image.Close()
+
+	imageInfo, err := image.Stat()
+	if err != nil {
+		return HouseCreateRequest{}, err
+	}
+
+	err = media.ValidateImageFile(imageInfo.Name(), imageInfo.Size())
+	if err != nil {
+		return HouseCreateRequest{}, fmt.Errorf("featured_image %s: %w", featuredImage, err)
+	}
+
+	return req, nil
+}
+
+func uploadImportImage(featuredImage string, images *zip.Reader) (string, error) {
+	if isRemoteImage(featuredImage) {
+		return media.UploadRemoteMedia("house", featuredImage)
+	}
+
+	image, err := openImportImage(featuredImage, images)
+	if err != nil {
+		return "", err
+	}
+	defer image.Close()
+
+	return media.UploadMediaReader("house", image)
+}
+
+func destroyImportImages(featuredImageURLs ...string) {
+	for _, v := range featuredImageURLs {
+		err := media.DestroyMedia(v)
+		if err != nil {
+			log.Printf("couldn't destroy imported image %s: %v", v, err)
+		}
+	}
+}
+
+func isRemoteImage(featuredImage string) bool {
+	return strings.HasPrefix(featuredImage, "http://") || strings.HasPrefix(featuredImage, "https://")
+}
+
+func openImportImage(name string, images *zip.Reader) (fs.File, error) {
+	if images == nil {
+		return nil, fmt.Errorf("featured_image %s is not an url while no images are sent", name)
+	}
+
+	image, err := images.Open(path.Clean(strings.TrimPrefix(name, "/")))
+	if err != nil {
+		return nil, fmt.Errorf("featured_image %s is not found in the images", name)
+	}
+
+	return image, nil
+}
+
+func readImportArchive(archive *multipart.FileHeader) ([]byte, error) {
+	if archive.Size > maxImportArchiveSize {
+		return nil, errors.New("invalid zip file size")
+	}
+
+	file, err := archive.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, errors.New("invalid zip file")
+	}
+
+	return content, nil
+}
+
+// readImportRowsFromArchive read the rows of the only csv or json file inside a zip
+func readImportRowsFromArchive(archive []byte) ([]map[string]string, error) {
+	archiveReader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, errors.New("invalid zip file")
+	}
+
+	var listingFile *zip.File
+	for _, v := range archiveReader.File {
+		ext := strings.ToLower(filepath.Ext(v.Name))
+		if ext != ".csv" && ext != ".json" {
+			continue
+		}
+		if listingFile != nil {
+			return nil, errors.New("the zip file should have only one csv or json file")
+		}
+		listingFile = v
+	}
+
+	if listingFile == nil {
+		return nil, errors.New("the zip file has no csv or json file")
+	}
+	if listingFile.UncompressedSize64 > maxImportFileSize {
+		return nil, errors.New("invalid file size")
+	}
+
+	file, err := listingFile.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return readImportRows(listingFile.Name, file)
+}
+
+func readImportRowsFromFile(listingFile *multipart.FileHeader) ([]map[string]string, error) {
+	if listingFile.Size > maxImportFileSize {
+		return nil, errors.New("invalid file size")
+	}
+
+	file, err := listingFile.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return readImportRows(listingFile.Filename, file)
+}
+
+// readImportRows read the listings of a csv file whose header names the fields, or a json file
+// with an array of listings
+func readImportRows(filename string, file io.Reader) ([]map[string]string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return readImportCSV(file)
+	case ".json":
+		return readImportJSON(file)
+	default:
+		return nil, errors.New("invalid file type")
+	}
+}
+
+func readImportCSV(file io.Reader) ([]map[string]string, error) {
+	csvReader := csv.NewReader(file)
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, errors.New("invalid csv file")
+	}
+	for i, v := range header {
+		// a spreadsheet app may start the file with a byte order mark
+		header[i] = strings.TrimSpace(strings.TrimPrefix(v, "\ufeff"))
+	}
+
+	rows := make([]map[string]string, 0)
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv file: %w", err)
+		}
+
+		row := make(map[string]string, len(header))
+		for i, v := range record {
+			row[header[i]] = strings.TrimSpace(v)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func readImportJSON(file io.Reader) ([]map[string]string, error) {
+	decoder := json.NewDecoder(file)
+	decoder.UseNumber()
+
+	var listings []map[string]interface{}
+	err := decoder.Decode(&listings)
+	if err != nil {
+		return nil, errors.New("invalid json file, it should be an array of listings")
+	}
+
+	rows := make([]map[string]string, 0, len(listings))
+	for i, listing := range listings {
+		row := make(map[string]string, len(listing))
+		for k, v := range listing {
+			switch v := v.(type) {
+			case nil:
+			case string:
+				row[k] = strings.TrimSpace(v)
+			case json.Number:
+				row[k] = v.String()
+			case bool:
+				row[k] = strconv.FormatBool(v)
+			default:
+				return nil, fmt.Errorf("invalid json file, %s of listing %d should be a string or a number", k, i+1)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func houseImportRowOf(houseImport sqlc.HouseImport) HouseImportRow {
+	importErrors := make([]HouseImportError, 0)
+	_ = json.Unmarshal(houseImport.Errors, &importErrors)
+
+	row := HouseImportRow{
+		ID:            houseImport.ID,
+		Mode:          houseImport.Mode,
+		Status:        houseImport.Status,
+		TotalRows:     houseImport.TotalRows,
+		ProcessedRows: houseImport.ProcessedRows,
+		CreatedRows:   houseImport.CreatedRows,
+		FailedRows:    len(importErrors),
+		Errors:        importErrors,
+		CreatedAt:     houseImport.CreatedAt,
+	}
+	if houseImport.FinishedAt.Valid {
+		row.FinishedAt = &houseImport.FinishedAt.Time
+	}
+
+	return row
+}
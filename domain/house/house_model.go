@@ -1,27 +1,264 @@
 package house
 
+import (
+	"errors"
+	"time"
+
+	sqlc "gubuk-service/db/sqlc"
+
+	"github.com/google/uuid"
+)
+
+// Status of a house listing, only a published house is shown on the public endpoints
+const (
+	HouseStatusDraft         = "draft"
+	HouseStatusPendingReview = "pending_review"
+	HouseStatusPublished     = "published"
+	HouseStatusUnlisted      = "unlisted"
+	HouseStatusArchived      = "archived"
+)
+
+// ErrUpcomingBooking is returned by CheckDeletable for a house which still has bookings to serve
+var ErrUpcomingBooking = errors.New("house has upcoming bookings, unlist it until they're over instead")
+
+// houseStatusTransitions list the statuses an owner could move a house to from each status
+var houseStatusTransitions = map[string][]string{
+	HouseStatusDraft:         {HouseStatusPublished, HouseStatusArchived},
+	HouseStatusPendingReview: {HouseStatusDraft, HouseStatusArchived},
+	HouseStatusPublished:     {HouseStatusUnlisted, HouseStatusArchived},
+	HouseStatusUnlisted:      {HouseStatusPublished, HouseStatusDraft, HouseStatusArchived},
+	HouseStatusArchived:      {HouseStatusDraft, HouseStatusPublished},
+}
+
 type HouseCreateRequest struct {
 	Title       string `form:"title" binding:"required"`
 	Bedrooms    int    `form:"bedrooms" binding:"required"`
 	Bathrooms   int    `form:"bathrooms" binding:"required"`
-	TypeRent    string `form:"type_rent" binding:"required"`
-	Price       int64  `form:"price" binding:"required"`
+	TypeRent    string `form:"type_rent" binding:"required,oneof=day month year"`
+	Price       int64  `form:"price" binding:"required,min=1,max=1000000000000"`
 	ProvinceID  int    `form:"province_id" binding:"required"`
 	CityID      int    `form:"city_id" binding:"required"`
 	Description string `form:"description" binding:"required"`
 	Amenities   string `form:"amenities"`
 	Area        int    `form:"area" binding:"required"`
+	Status      string `form:"status" binding:"omitempty,oneof=draft published"`
+
+	SecurityDeposit int64 `form:"security_deposit" binding:"omitempty,min=0,max=1000000000000"`
+	CleaningFee     int64 `form:"cleaning_fee" binding:"omitempty,min=0,max=1000000000000"`
+	ServiceFee      int64 `form:"service_fee" binding:"omitempty,min=0,max=1000000000000"`
+	WeeklyDiscount  int32 `form:"weekly_discount" binding:"omitempty,min=0,max=100"`
+	MonthlyDiscount int32 `form:"monthly_discount" binding:"omitempty,min=0,max=100"`
+	TaxRate         int32 `form:"tax_rate" binding:"omitempty,min=0,max=100"`
 }
 
+// HouseUpdateRequest is filled with the current house before it's bound, so a field the owner doesn't send
+// is kept as it is while the rules apply to the house as it would be saved
 type HouseUpdateRequest struct {
-	Title       string `form:"title" binding:"required"`
-	Bedrooms    int    `form:"bedrooms" binding:"required"`
-	Bathrooms   int    `form:"bathrooms" binding:"required"`
-	TypeRent    string `form:"type_rent" binding:"required"`
-	Price       int64  `form:"price" binding:"required"`
-	ProvinceID  int    `form:"province_id" binding:"required"`
-	CityID      int    `form:"city_id" binding:"required"`
-	Description string `form:"description" binding:"required"`
-	Amenities   string `form:"amenities"`
-	Area        int    `form:"area" binding:"required"`
+	Title       string `form:"title" json:"title" binding:"required"`
+	Bedrooms    int    `form:"bedrooms" json:"bedrooms" binding:"required"`
+	Bathrooms   int    `form:"bathrooms" json:"bathrooms" binding:"required"`
+	TypeRent    string `form:"type_rent" json:"type_rent" binding:"required,oneof=day month year"`
+	Price       int64  `form:"price" json:"price" binding:"required,min=1,max=1000000000000"`
+	ProvinceID  int    `form:"province_id" json:"province_id" binding:"required"`
+	CityID      int    `form:"city_id" json:"city_id" binding:"required"`
+	Description string `form:"description" json:"description" binding:"required"`
+	Amenities   string `form:"amenities" json:"amenities"`
+	Area        int    `form:"area" json:"area" binding:"required"`
+
+	SecurityDeposit int64 `form:"security_deposit" json:"security_deposit" binding:"omitempty,min=0,max=1000000000000"`
+	CleaningFee     int64 `form:"cleaning_fee" json:"cleaning_fee" binding:"omitempty,min=0,max=1000000000000"`
+	ServiceFee      int64 `form:"service_fee" json:"service_fee" binding:"omitempty,min=0,max=1000000000000"`
+	WeeklyDiscount  int32 `form:"weekly_discount" json:"weekly_discount" binding:"omitempty,min=0,max=100"`
+	MonthlyDiscount int32 `form:"monthly_discount" json:"monthly_discount" binding:"omitempty,min=0,max=100"`
+	TaxRate         int32 `form:"tax_rate" json:"tax_rate" binding:"omitempty,min=0,max=100"`
+}
+
+type HouseStatusUpdateRequest struct {
+	Status string `form:"status" json:"status" binding:"required,oneof=draft published unlisted archived"`
+}
+
+type HousePriceRuleCreateRequest struct {
+	Name      string    `form:"name" binding:"required"`
+	StartDate time.Time `form:"start_date" time_format:"2006-01-02" time_utc:"1"`
+	EndDate   time.Time `form:"end_date" time_format:"2006-01-02" time_utc:"1"`
+	Weekdays  string    `form:"weekdays"`
+	Price     int64     `form:"price" binding:"required,min=1,max=1000000000000"`
+	MinStay   int       `form:"min_stay" binding:"omitempty,min=0,max=90"`
+}
+
+type HousePriceRuleRow struct {
+	ID        uuid.UUID `json:"id"`
+	HouseID   uuid.UUID `json:"house_id"`
+	Name      string    `json:"name"`
+	StartDate string    `json:"start_date"`
+	EndDate   string    `json:"end_date"`
+	Weekdays  string    `json:"weekdays"`
+	Price     int64     `json:"price"`
+	MinStay   int32     `json:"min_stay"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type HouseQuoteRequest struct {
+	CheckIn  time.Time `form:"check_in" time_format:"2006-01-02" time_utc:"1" binding:"required"`
+	TimeRent int       `form:"time_rent" binding:"required,min=1"`
+
+	RentalPlanID string `form:"rental_plan_id"`
+}
+
+type HouseRentalPlanRequest struct {
+	Unit        string `form:"unit" binding:"required,oneof=day month year"`
+	Price       int64  `form:"price" binding:"required,min=1,max=1000000000000"`
+	MinDuration int    `form:"min_duration" binding:"omitempty,min=1"`
+	MaxDuration int    `form:"max_duration" binding:"omitempty,min=1"`
+}
+
+// HouseFilter is the filter of the house list, a saved search keeps one to match new houses with
+type HouseFilter struct {
+	Bedrooms   int     `form:"bedrooms" json:"bedrooms,omitempty" binding:"min=0"`
+	Bathrooms  int     `form:"bathrooms" json:"bathrooms,omitempty" binding:"min=0"`
+	TypeRent   string  `form:"type_rent" json:"type_rent,omitempty" binding:"omitempty,oneof=day month year"`
+	Price      int     `form:"price" json:"price,omitempty" binding:"min=0"`
+	ProvinceID int     `form:"province_id" json:"province_id,omitempty" binding:"min=0"`
+	CityID     int     `form:"city_id" json:"city_id,omitempty" binding:"min=0"`
+	Amenities  string  `form:"amenities" json:"amenities,omitempty"`
+	Rating     float64 `form:"rating" json:"rating,omitempty" binding:"min=0,max=5"`
+}
+
+type HouseListRow struct {
+	sqlc.ListHouseRow
+	RentalPlans   []sqlc.HouseRentalPlan `json:"rental_plans"`
+	RatingAverage float64                `json:"rating_average"`
+	RatingCount   int64                  `json:"rating_count"`
+	IsFavorite    bool                   `json:"is_favorite"`
+}
+
+type MyHouseListRow struct {
+	sqlc.ListMyHouseRow
+	RentalPlans   []sqlc.HouseRentalPlan `json:"rental_plans"`
+	FavoriteCount int64                  `json:"favorite_count"`
+}
+
+type HouseDetail struct {
+	sqlc.GetHouseByIdRow
+	RentalPlans         []sqlc.HouseRentalPlan `json:"rental_plans"`
+	RatingAverage       float64                `json:"rating_average"`
+	RatingCount         int64                  `json:"rating_count"`
+	OwnerContactVisible bool                   `json:"owner_contact_visible"`
+	IsFavorite          bool                   `json:"is_favorite"`
+	// FavoriteCount is only shown to the owner of the house
+	FavoriteCount *int64 `json:"favorite_count,omitempty"`
+}
+
+type OwnerProfile struct {
+	Fullname      string         `json:"fullname"`
+	Username      string         `json:"username"`
+	Avatar        string         `json:"avatar"`
+	JoinedAt      time.Time      `json:"joined_at"`
+	ListingCount  int            `json:"listing_count"`
+	RatingAverage float64        `json:"rating_average"`
+	RatingCount   int64          `json:"rating_count"`
+	ResponseRate  *float64       `json:"response_rate"`
+	Houses        []HouseListRow `json:"houses"`
+}
+
+// Channels & frequencies a saved search could alert it's user with, a daily saved search
+// collects the matches of a day into one digest
+const (
+	SavedSearchAlertNotification = "notification"
+	SavedSearchAlertEmail        = "email"
+	SavedSearchAlertInstant      = "instant"
+	SavedSearchAlertDaily        = "daily"
+)
+
+type SavedSearchCreateRequest struct {
+	HouseFilter
+	Name           string `form:"name" binding:"required"`
+	AlertChannel   string `form:"alert_channel" binding:"omitempty,oneof=notification email"`
+	AlertFrequency string `form:"alert_frequency" binding:"omitempty,oneof=instant daily"`
+}
+
+type SavedSearchRow struct {
+	ID             uuid.UUID   `json:"id"`
+	Name           string      `json:"name"`
+	Filter         HouseFilter `json:"filter"`
+	AlertChannel   string      `json:"alert_channel"`
+	AlertFrequency string      `json:"alert_frequency"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+type OwnerStatsRequest struct {
+	From time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To   time.Time `form:"to" time_format:"2006-01-02" time_utc:"1"`
+}
+
+// StatsMetrics are the metrics of the owner's houses over a period, the bookings are counted by the date
+// they're made while the occupancy counts the approved nights stayed within the period
+type StatsMetrics struct {
+	Revenue          int64            `json:"revenue"`
+	BookingCount     int64            `json:"booking_count"`
+	BookingsByStatus map[string]int64 `json:"bookings_by_status"`
+	OccupiedDays     float64          `json:"occupied_days"`
+	OccupancyRate    float64          `json:"occupancy_rate"`
+	AverageLeadDays  *float64         `json:"average_lead_days"`
+	ViewCount        int64            `json:"view_count"`
+	ConversionRate   *float64         `json:"conversion_rate"`
+}
+
+type MonthlyStats struct {
+	Month string `json:"month"`
+	StatsMetrics
+}
+
+type HouseStats struct {
+	HouseID uuid.UUID `json:"house_id"`
+	Title   string    `json:"title"`
+	Status  string    `json:"status"`
+	StatsMetrics
+	Monthly []MonthlyStats `json:"monthly"`
+}
+
+type OwnerStats struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	StatsMetrics
+	Monthly []MonthlyStats `json:"monthly"`
+	Houses  []HouseStats   `json:"houses"`
+}
+
+// Modes of a house import, an all or nothing import creates no house at all when any of it's rows is invalid
+// while a partial import creates the valid rows & reports the others
+const (
+	HouseImportAllOrNothing = "all_or_nothing"
+	HouseImportPartial      = "partial"
+)
+
+// Status of a house import, a pending or processing import is picked up by the worker once it's lease runs out
+const (
+	HouseImportPending    = "pending"
+	HouseImportProcessing = "processing"
+	HouseImportCompleted  = "completed"
+	HouseImportFailed     = "failed"
+)
+
+type HouseImportCreateRequest struct {
+	Mode string `form:"mode" binding:"omitempty,oneof=all_or_nothing partial"`
+}
+
+// HouseImportError is why a row of a house import is not created, the first listing of the file is row 1
+type HouseImportError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+type HouseImportRow struct {
+	ID            uuid.UUID          `json:"id"`
+	Mode          string             `json:"mode"`
+	Status        string             `json:"status"`
+	TotalRows     int32              `json:"total_rows"`
+	ProcessedRows int32              `json:"processed_rows"`
+	CreatedRows   int32              `json:"created_rows"`
+	FailedRows    int                `json:"failed_rows"`
+	Errors        []HouseImportError `json:"errors"`
+	CreatedAt     time.Time          `json:"created_at"`
+	FinishedAt    *time.Time         `json:"finished_at"`
 }
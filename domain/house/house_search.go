@@ -0,0 +1,300 @@
+package house
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	db "gubuk-service/db"
+	sqlc "gubuk-service/db/sqlc"
+	"gubuk-service/event"
+	"gubuk-service/util"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	digestPollInterval = 5 * time.Minute
+	digestInterval     = 24 * time.Hour
+	digestBatchSize    = 100
+)
+
+func init() {
+	event.Subscribe(matchSavedSearch)
+}
+
+func CreateSavedSearch(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	var req SavedSearchCreateRequest
+	err = c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	if req.AlertChannel == "" {
+		req.AlertChannel = SavedSearchAlertNotification
+	}
+	if req.AlertFrequency == "" {
+		req.AlertFrequency = SavedSearchAlertInstant
+	}
+
+	filter, err := json.Marshal(req.HouseFilter)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	newSavedSearch, err := db.Queries.CreateSavedSearch(context.TODO(), sqlc.CreateSavedSearchParams{
+		ID:             uuid.New(),
+		UserID:         id,
+		Name:           req.Name,
+		Filter:         filter,
+		AlertChannel:   req.AlertChannel,
+		AlertFrequency: req.AlertFrequency,
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, savedSearchRowOf(newSavedSearch))
+}
+
+func ListSavedSearch(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	savedSearches, err := db.Queries.ListSavedSearchByUser(context.TODO(), id)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	savedSearchList := make([]SavedSearchRow, 0, len(savedSearches))
+	for _, v := range savedSearches {
+		savedSearchList = append(savedSearchList, savedSearchRowOf(v))
+	}
+
+	util.SendSuccess(c, savedSearchList)
+}
+
+func DeleteSavedSearch(c *gin.Context) {
+	savedSearch, err := getSavedSearch(c)
+	if err != nil {
+		util.SendNotFound(c, err)
+		return
+	}
+
+	err = db.Queries.DeleteSavedSearch(context.TODO(), savedSearch.ID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, nil)
+}
+
+func ListSavedSearchMatch(c *gin.Context) {
+	savedSearch, err := getSavedSearch(c)
+	if err != nil {
+		util.SendNotFound(c, err)
+		return
+	}
+
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+
+	// a matched house which is no longer published is left out, like on the house list
+	houseList, err := listHouse(userPayload, houseListQueryBuilder().Where(sq.Expr("id IN (SELECT house_id FROM saved_search_matches WHERE saved_search_id = ?)", savedSearch.ID)).OrderBy("created_at DESC"))
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, houseList)
+}
+
+// AnnouncePublished publish the event of a house which is just published, so it's matched
+// with the saved searches
+func AnnouncePublished(houseID uuid.UUID, ownerID uuid.UUID, title string) {
+	event.Publish(event.Event{
+		Type:    event.HousePublished,
+		OwnerID: ownerID,
+		Message: title + " is published",
+		Data: map[string]interface{}{
+			"house_id": houseID,
+			"title":    title,
+		},
+	})
+}
+
+// StartSavedSearchDigestWorker alert the users of the daily saved searches in the background,
+// once a day at most with every house matched since their last digest
+func StartSavedSearchDigestWorker() {
+	go func() {
+		ticker := time.NewTicker(digestPollInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			alertDueDigest()
+		}
+	}()
+}
+
+func alertDueDigest() {
+	now := time.Now()
+	savedSearches, err := db.Queries.ClaimDueSavedSearchDigest(context.TODO(), sqlc.ClaimDueSavedSearchDigestParams{
+		AlertedAt: now,
+		DueBefore: now.Add(-digestInterval),
+		BatchSize: digestBatchSize,
+	})
+	if err != nil {
+		log.Printf("couldn't claim saved search digests: %v", err)
+		return
+	}
+
+	for _, v := range savedSearches {
+		alertSavedSearch(v)
+	}
+}
+
+// matchSavedSearch record a newly published house as a match of every saved search it meets, all of them
+// are matched by a single query the same way the house would be found on the house list with their filter,
+// the user of an instant saved search is alerted right away while a daily one waits for the digest
+func matchSavedSearch(e event.Event) {
+	if e.Type != event.HousePublished {
+		return
+	}
+
+	houseID, ok := e.Data["house_id"].(uuid.UUID)
+	if !ok {
+		return
+	}
+
+	// only the newly matched saved searches are returned, a house published again after being unlisted
+	// was already matched & alerted about
+	savedSearches, err := db.Queries.MatchSavedSearch(context.TODO(), houseID)
+	if err != nil {
+		log.Printf("couldn't match house %s with the saved searches: %v", houseID, err)
+		return
+	}
+
+	for _, v := range savedSearches {
+		if v.AlertFrequency == SavedSearchAlertInstant {
+			alertSavedSearch(v)
+		}
+	}
+}
+
+// alertSavedSearch tell the user of a saved search about the houses matched since it's last alert,
+// through a notification or an email depending on the channel of the saved search
+func alertSavedSearch(savedSearch sqlc.SavedSearch) {
+	// the matches are marked as alerted as they're claimed, so a match is only ever alerted once
+	// even when the digest & an instant alert of the same saved search run at once
+	matches, err := db.Queries.ClaimUnalertedSavedSearchMatch(context.TODO(), sqlc.ClaimUnalertedSavedSearchMatchParams{
+		AlertedAt:     time.Now(),
+		SavedSearchID: savedSearch.ID,
+	})
+	if err != nil {
+		log.Printf("couldn't claim matches of saved search %s: %v", savedSearch.ID, err)
+		return
+	}
+
+	if len(matches) == 0 {
+		return
+	}
+
+	houses := make([]map[string]interface{}, 0, len(matches))
+	for _, v := range matches {
+		houses = append(houses, map[string]interface{}{
+			"house_id": v.ID,
+			"title":    v.Title,
+			"price":    v.Price,
+		})
+	}
+
+	message := matches[0].Title + " matches your saved search " + savedSearch.Name
+	if len(matches) > 1 {
+		message = fmt.Sprintf("%d new houses match your saved search %s", len(matches), savedSearch.Name)
+	}
+
+	e := event.Event{
+		Type:    event.SavedSearchMatched,
+		Message: message,
+		Data: map[string]interface{}{
+			"saved_search_id": savedSearch.ID,
+			"name":            savedSearch.Name,
+			"user_id":         savedSearch.UserID,
+			"channel":         savedSearch.AlertChannel,
+			"houses":          houses,
+		},
+	}
+	if savedSearch.AlertChannel == SavedSearchAlertNotification {
+		e.Recipients = []uuid.UUID{savedSearch.UserID}
+	}
+	event.Publish(e)
+}
+
+// getSavedSearch return the saved search referred by the id param, a saved search of another user is not exist
+func getSavedSearch(c *gin.Context) (sqlc.SavedSearch, error) {
+	errNotExist := errors.New("saved search with the provided id is not exist")
+
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return sqlc.SavedSearch{}, errNotExist
+	}
+
+	savedSearch, err := db.Queries.GetSavedSearchById(context.TODO(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return sqlc.SavedSearch{}, errNotExist
+		}
+		return sqlc.SavedSearch{}, err
+	}
+
+	if savedSearch.UserID.String() != userPayload.UserID {
+		return sqlc.SavedSearch{}, errNotExist
+	}
+
+	return savedSearch, nil
+}
+
+func savedSearchRowOf(savedSearch sqlc.SavedSearch) SavedSearchRow {
+	var filter HouseFilter
+	_ = json.Unmarshal(savedSearch.Filter, &filter)
+
+	return SavedSearchRow{
+		ID:             savedSearch.ID,
+		Name:           savedSearch.Name,
+		Filter:         filter,
+		AlertChannel:   savedSearch.AlertChannel,
+		AlertFrequency: savedSearch.AlertFrequency,
+		CreatedAt:      savedSearch.CreatedAt,
+	}
+}
@@ -0,0 +1,274 @@
+package house
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	db "gubuk-service/db"
+	sqlc "gubuk-service/db/sqlc"
+	"gubuk-service/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxStatsMonths is the longest period the stats could be asked for at once
+const maxStatsMonths = 36
+
+// statsCounter sums up the raw numbers the metrics of a house and/or a month are computed from
+type statsCounter struct {
+	revenue          int64
+	bookingCount     int64
+	bookingsByStatus map[string]int64
+	leadDays         float64
+	occupiedDays     float64
+	availableDays    float64
+	viewCount        int64
+}
+
+func newStatsCounter() *statsCounter {
+	return &statsCounter{
+		bookingsByStatus: make(map[string]int64),
+	}
+}
+
+func (s *statsCounter) metrics() StatsMetrics {
+	metrics := StatsMetrics{
+		Revenue:          s.revenue,
+		BookingCount:     s.bookingCount,
+		BookingsByStatus: s.bookingsByStatus,
+		OccupiedDays:     s.occupiedDays,
+		ViewCount:        s.viewCount,
+	}
+
+	if s.availableDays > 0 {
+		metrics.OccupancyRate = s.occupiedDays / s.availableDays
+	}
+
+	// the lead time & the conversion are left empty when there's nothing to compute them from
+	if s.bookingCount > 0 {
+		averageLeadDays := s.leadDays / float64(s.bookingCount)
+		metrics.AverageLeadDays = &averageLeadDays
+	}
+
+	if s.viewCount > 0 {
+		conversionRate := float64(s.bookingCount) / float64(s.viewCount)
+		metrics.ConversionRate = &conversionRate
+	}
+
+	return metrics
+}
+
+func GetOwnerStats(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	var req OwnerStatsRequest
+	err = c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	// the last 12 months up to today are shown by default
+	if req.To.IsZero() {
+		req.To = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+	if req.From.IsZero() {
+		req.From = time.Date(req.To.Year(), req.To.Month()-11, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	if req.To.Before(req.From) {
+		util.SendBadRequest(c, errors.New("to should not be before from"))
+		return
+	}
+
+	// the to date is included in the period
+	startAt := req.From
+	endAt := req.To.AddDate(0, 0, 1)
+
+	months := monthsBetween(startAt, endAt)
+	if len(months) > maxStatsMonths {
+		util.SendBadRequest(c, errors.New("the period should not be longer than 36 months"))
+		return
+	}
+
+	// a house deleted before or created after the period is left out, so it doesn't lower the occupancy
+	houses, err := db.Queries.ListStatHouseByOwner(context.TODO(), sqlc.ListStatHouseByOwnerParams{
+		OwnerID: ownerID,
+		StartAt: sql.NullTime{Time: startAt, Valid: true},
+		EndAt:   endAt,
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	bookingStats, err := db.Queries.ListMonthlyBookingStatByOwner(context.TODO(), sqlc.ListMonthlyBookingStatByOwnerParams{
+		OwnerID: ownerID,
+		StartAt: startAt,
+		EndAt:   endAt,
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	occupancies, err := db.Queries.ListMonthlyOccupancyByOwner(context.TODO(), sqlc.ListMonthlyOccupancyByOwnerParams{
+		EndAt:   endAt,
+		StartAt: startAt,
+		OwnerID: ownerID,
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	views, err := db.Queries.ListMonthlyHouseViewByOwner(context.TODO(), sqlc.ListMonthlyHouseViewByOwnerParams{
+		OwnerID: ownerID,
+		StartAt: startAt,
+		EndAt:   endAt,
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	total := newStatsCounter()
+	monthly := make(map[string]*statsCounter)
+	houseTotal := make(map[uuid.UUID]*statsCounter)
+	houseMonthly := make(map[uuid.UUID]map[string]*statsCounter)
+	for _, m := range months {
+		monthly[monthKeyOf(m)] = newStatsCounter()
+	}
+	for _, h := range houses {
+		houseTotal[h.ID] = newStatsCounter()
+		houseMonthly[h.ID] = make(map[string]*statsCounter)
+		for _, m := range months {
+			houseMonthly[h.ID][monthKeyOf(m)] = newStatsCounter()
+		}
+	}
+
+	// record add up a number of a house in a month, along with the totals it's part of
+	record := func(houseID uuid.UUID, month time.Time, add func(*statsCounter)) {
+		houseMonth, ok := houseMonthly[houseID][monthKeyOf(month)]
+		if !ok {
+			return
+		}
+
+		add(houseMonth)
+		add(houseTotal[houseID])
+		add(monthly[monthKeyOf(month)])
+		add(total)
+	}
+
+	// a house is only available while it exists, from it's creation until it's deletion
+	for _, h := range houses {
+		availableFrom, availableUntil := startAt, endAt
+		if h.CreatedAt.After(availableFrom) {
+			availableFrom = h.CreatedAt
+		}
+		if h.DeletedAt.Valid && h.DeletedAt.Time.Before(availableUntil) {
+			availableUntil = h.DeletedAt.Time
+		}
+
+		for _, m := range months {
+			availableDays := daysWithin(m, m.AddDate(0, 1, 0), availableFrom, availableUntil)
+			record(h.ID, m, func(s *statsCounter) {
+				s.availableDays += availableDays
+			})
+		}
+	}
+
+	for _, v := range bookingStats {
+		v := v
+		record(v.HouseID, v.Month, func(s *statsCounter) {
+			s.bookingCount += v.BookingCount
+			s.bookingsByStatus[v.PaymentStatus] += v.BookingCount
+			s.leadDays += v.LeadDays
+			if v.PaymentStatus == "approved" {
+				s.revenue += v.Revenue
+			}
+		})
+	}
+
+	for _, v := range occupancies {
+		v := v
+		record(v.HouseID, v.Month, func(s *statsCounter) {
+			s.occupiedDays += v.OccupiedDays
+		})
+	}
+
+	for _, v := range views {
+		v := v
+		record(v.HouseID, v.Month, func(s *statsCounter) {
+			s.viewCount += v.ViewCount
+		})
+	}
+
+	monthlyStatsOf := func(counters map[string]*statsCounter) []MonthlyStats {
+		monthlyStats := make([]MonthlyStats, 0, len(months))
+		for _, m := range months {
+			monthlyStats = append(monthlyStats, MonthlyStats{
+				Month:        monthKeyOf(m),
+				StatsMetrics: counters[monthKeyOf(m)].metrics(),
+			})
+		}
+		return monthlyStats
+	}
+
+	houseStats := make([]HouseStats, 0, len(houses))
+	for _, h := range houses {
+		houseStats = append(houseStats, HouseStats{
+			HouseID:      h.ID,
+			Title:        h.Title,
+			Status:       h.Status,
+			StatsMetrics: houseTotal[h.ID].metrics(),
+			Monthly:      monthlyStatsOf(houseMonthly[h.ID]),
+		})
+	}
+
+	util.SendSuccess(c, OwnerStats{
+		From:         req.From.Format("2006-01-02"),
+		To:           req.To.Format("2006-01-02"),
+		StatsMetrics: total.metrics(),
+		Monthly:      monthlyStatsOf(monthly),
+		Houses:       houseStats,
+	})
+}
+
+// monthsBetween list the first day of every month the period overlaps
+func monthsBetween(startAt time.Time, endAt time.Time) []time.Time {
+	months := make([]time.Time, 0)
+	for m := time.Date(startAt.Year(), startAt.Month(), 1, 0, 0, 0, 0, time.UTC); m.Before(endAt); m = m.AddDate(0, 1, 0) {
+		months = append(months, m)
+	}
+	return months
+}
+
+func monthKeyOf(month time.Time) string {
+	return month.Format("2006-01")
+}
+
+// daysWithin count the days two periods overlap
+func daysWithin(startAt time.Time, endAt time.Time, periodStartAt time.Time, periodEndAt time.Time) float64 {
+	if periodStartAt.After(startAt) {
+		startAt = periodStartAt
+	}
+	if periodEndAt.Before(endAt) {
+		endAt = periodEndAt
+	}
+	if !endAt.After(startAt) {
+		return 0
+	}
+	return endAt.Sub(startAt).Hours() / 24
+}
@@ -0,0 +1,256 @@
+// Package idempotency makes a retried request safe to send again, the response of the first request with
+// an Idempotency-Key is stored & replayed to it's repeats instead of running the handler twice
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"time"
+
+	"gubuk-service/config"
+	db "gubuk-service/db"
+	sqlc "gubuk-service/db/sqlc"
+	"gubuk-service/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const keyCompleted = "completed"
+
+const (
+	maxKeyLength = 255
+	// keyLease is how long a key is locked by the request processing it, a request which never
+	// finished (e.g. the service restarted) could be retried with the same key once it runs out
+	keyLease        = 5 * time.Minute
+	cleanupInterval = time.Hour
+	// maxBodySize is the largest body which is read into memory to be fingerprinted, it fits
+	// a payment proof along with the fields of it's form
+	maxBodySize = 2 << 20
+)
+
+// keyStore is where the keys & their responses are stored, it's db.Queries outside of the tests
+type keyStore interface {
+	ClaimIdempotencyKey(ctx context.Context, arg sqlc.ClaimIdempotencyKeyParams) (sqlc.IdempotencyKey, error)
+	GetIdempotencyKey(ctx context.Context, arg sqlc.GetIdempotencyKeyParams) (sqlc.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, arg sqlc.CompleteIdempotencyKeyParams) error
+	DeleteIdempotencyKey(ctx context.Context, arg sqlc.DeleteIdempotencyKeyParams) error
+}
+
+var store keyStore = db.Queries
+
+// VerifyKey run the handler only once for the same Idempotency-Key of a user, a repeat gets the stored
+// response of the first request while a request reusing the key with a different body is rejected,
+// it comes after VerifyAuth as the keys are scoped by user
+func VerifyKey(c *gin.Context) {
+	key := c.GetHeader("Idempotency-Key")
+	if key == "" {
+		c.Next()
+		return
+	}
+
+	if len(key) > maxKeyLength {
+		util.SendBadRequest(c, fmt.Errorf("Idempotency-Key should not be longer than %d characters", maxKeyLength))
+		return
+	}
+
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+
+	userID, err := uuid.Parse(userPayload.UserID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBodySize)
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		// the reader stops right at the limit when the body is larger than it
+		if len(body) == maxBodySize {
+			util.SendRequestEntityTooLarge(c, fmt.Errorf("request body should not be larger than %d MB", maxBodySize>>20))
+			return
+		}
+
+		util.SendBadRequest(c, err)
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	fingerprint, err := fingerprintOf(c.Request, body)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	now := time.Now()
+	_, err = store.ClaimIdempotencyKey(context.TODO(), sqlc.ClaimIdempotencyKeyParams{
+		UserID:      userID,
+		Key:         key,
+		Fingerprint: fingerprint,
+		LockedUntil: now.Add(keyLease),
+		ExpiresAt:   now.Add(config.IdempotencyKeyTTL),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		replay(c, userID, key, fingerprint)
+		return
+	}
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	// the key of a request which failed or panicked is released, so it could be retried
+	completed := false
+	defer func() {
+		if completed {
+			return
+		}
+
+		err := store.DeleteIdempotencyKey(context.TODO(), sqlc.DeleteIdempotencyKeyParams{
+			UserID: userID,
+			Key:    key,
+		})
+		if err != nil {
+			log.Printf("couldn't release idempotency key %s of user %s: %v", key, userID, err)
+		}
+	}()
+
+	recorder := &responseRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+
+	c.Next()
+
+	if recorder.Status() >= http.StatusInternalServerError {
+		return
+	}
+
+	err = store.CompleteIdempotencyKey(context.TODO(), sqlc.CompleteIdempotencyKeyParams{
+		UserID:              userID,
+		Key:                 key,
+		ResponseCode:        int32(recorder.Status()),
+		ResponseContentType: recorder.Header().Get("Content-Type"),
+		ResponseBody:        recorder.body.Bytes(),
+	})
+	if err != nil {
+		log.Printf("couldn't store response of idempotency key %s of user %s: %v", key, userID, err)
+		return
+	}
+
+	completed = true
+}
+
+// replay respond to a repeated key with the stored response of it's first request
+func replay(c *gin.Context, userID uuid.UUID, key string, fingerprint string) {
+	idempotencyKey, err := store.GetIdempotencyKey(context.TODO(), sqlc.GetIdempotencyKeyParams{
+		UserID: userID,
+		Key:    key,
+	})
+	if err != nil {
+		// the first request is just released, so there's nothing to replay yet
+		if errors.Is(err, sql.ErrNoRows) {
+			util.SendConflict(c, errors.New("the request with the same Idempotency-Key is just failed, please retry"))
+			return
+		}
+
+		util.SendServerError(c, err)
+		return
+	}
+
+	if idempotencyKey.Fingerprint != fingerprint {
+		util.SendUnprocessableEntity(c, errors.New("Idempotency-Key is already used by a different request"))
+		return
+	}
+
+	if idempotencyKey.Status != keyCompleted {
+		util.SendConflict(c, errors.New("the request with the same Idempotency-Key is still being processed"))
+		return
+	}
+
+	c.Header("Idempotent-Replayed", "true")
+	c.Data(int(idempotencyKey.ResponseCode), idempotencyKey.ResponseContentType, idempotencyKey.ResponseBody)
+	c.Abort()
+}
+
+// fingerprintOf hash the method, the url & the body of a request, the parts of a multipart body are
+// hashed instead of the body itself as it's boundary is random for every request
+func fingerprintOf(req *http.Request, body []byte) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", req.Method, req.URL.RequestURI())
+
+	mediaType, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		hash.Write(body)
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	}
+
+	parts := make([]string, 0)
+	multipartReader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := multipartReader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		partHash := sha256.New()
+		_, err = io.Copy(partHash, part)
+		if err != nil {
+			return "", err
+		}
+
+		parts = append(parts, fmt.Sprintf("%s %s %x", part.FormName(), part.FileName(), partHash.Sum(nil)))
+	}
+
+	sort.Strings(parts)
+	for _, v := range parts {
+		fmt.Fprintln(hash, v)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// responseRecorder keep a copy of the response written to the client, so it could be replayed
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// StartCleanupWorker delete the expired keys in the background, an expired key could be used again
+// anyway but it's stored response shouldn't be kept longer than needed
+func StartCleanupWorker() {
+	go func() {
+		ticker := time.NewTicker(cleanupInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			_, err := db.Queries.DeleteExpiredIdempotencyKey(context.TODO())
+			if err != nil {
+				log.Printf("couldn't delete expired idempotency keys: %v", err)
+			}
+		}
+	}()
+}
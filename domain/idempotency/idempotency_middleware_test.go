@@ -0,0 +1,215 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sqlc "gubuk-service/db/sqlc"
+	"gubuk-service/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// memoryStore keep the keys in memory the way the idempotency_keys queries do
+type memoryStore struct {
+	mu   sync.Mutex
+	keys map[string]sqlc.IdempotencyKey
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{keys: make(map[string]sqlc.IdempotencyKey)}
+}
+
+func (s *memoryStore) ClaimIdempotencyKey(ctx context.Context, arg sqlc.ClaimIdempotencyKeyParams) (sqlc.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	id := arg.UserID.String() + "/" + arg.Key
+	existing, ok := s.keys[id]
+	if ok && existing.ExpiresAt.After(now) && (existing.Status != "processing" || existing.LockedUntil.After(now) || existing.Fingerprint != arg.Fingerprint) {
+		return sqlc.IdempotencyKey{}, sql.ErrNoRows
+	}
+
+	claimed := sqlc.IdempotencyKey{
+		UserID:      arg.UserID,
+		Key:         arg.Key,
+		Fingerprint: arg.Fingerprint,
+		Status:      "processing",
+		LockedUntil: arg.LockedUntil,
+		ExpiresAt:   arg.ExpiresAt,
+	}
+	s.keys[id] = claimed
+	return claimed, nil
+}
+
+func (s *memoryStore) GetIdempotencyKey(ctx context.Context, arg sqlc.GetIdempotencyKeyParams) (sqlc.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[arg.UserID.String()+"/"+arg.Key]
+	if !ok {
+		return sqlc.IdempotencyKey{}, sql.ErrNoRows
+	}
+	return key, nil
+}
+
+func (s *memoryStore) CompleteIdempotencyKey(ctx context.Context, arg sqlc.CompleteIdempotencyKeyParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := arg.UserID.String() + "/" + arg.Key
+	key := s.keys[id]
+	key.Status = keyCompleted
+	key.ResponseCode = arg.ResponseCode
+	key.ResponseContentType = arg.ResponseContentType
+	key.ResponseBody = arg.ResponseBody
+	s.keys[id] = key
+	return nil
+}
+
+func (s *memoryStore) DeleteIdempotencyKey(ctx context.Context, arg sqlc.DeleteIdempotencyKeyParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.keys, arg.UserID.String()+"/"+arg.Key)
+	return nil
+}
+
+// newRouter serve handler behind VerifyKey for a signed in user, with the keys kept in memory
+func newRouter(t *testing.T, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	previousStore := store
+	store = newMemoryStore()
+	t.Cleanup(func() { store = previousStore })
+
+	userID := uuid.New().String()
+	router := gin.New()
+	router.POST("/bookings", func(c *gin.Context) {
+		c.Set("user", &util.UserPayload{UserID: userID})
+	}, VerifyKey, handler)
+
+	return router
+}
+
+func send(router *gin.Engine, key string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/bookings", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", key)
+
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+	return res
+}
+
+func TestVerifyKeyReplay(t *testing.T) {
+	calls := 0
+	router := newRouter(t, func(c *gin.Context) {
+		calls++
+		util.SendSuccess(c, gin.H{"booking": calls})
+	})
+
+	first := send(router, "key-1", `{"house_id":"a"}`)
+	repeat := send(router, "key-1", `{"house_id":"a"}`)
+
+	if calls != 1 {
+		t.Fatalf("handler is called %d times, want 1", calls)
+	}
+	if repeat.Code != first.Code || !bytes.Equal(repeat.Body.Bytes(), first.Body.Bytes()) {
+		t.Errorf("repeat got %d %s, want the first response %d %s", repeat.Code, repeat.Body, first.Code, first.Body)
+	}
+	if repeat.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("repeat is not marked as replayed")
+	}
+	if first.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("first response is marked as replayed")
+	}
+}
+
+func TestVerifyKeyFingerprintMismatch(t *testing.T) {
+	calls := 0
+	router := newRouter(t, func(c *gin.Context) {
+		calls++
+		util.SendSuccess(c, nil)
+	})
+
+	send(router, "key-1", `{"house_id":"a"}`)
+	res := send(router, "key-1", `{"house_id":"b"}`)
+
+	if res.Code != http.StatusUnprocessableEntity {
+		t.Errorf("got %d, want %d", res.Code, http.StatusUnprocessableEntity)
+	}
+	if calls != 1 {
+		t.Errorf("handler is called %d times, want 1", calls)
+	}
+}
+
+func TestVerifyKeyInFlight(t *testing.T) {
+	var router *gin.Engine
+	var inFlight *httptest.ResponseRecorder
+	router = newRouter(t, func(c *gin.Context) {
+		// the repeat comes while the first request is still being processed
+		inFlight = send(router, "key-1", `{"house_id":"a"}`)
+		util.SendSuccess(c, nil)
+	})
+
+	first := send(router, "key-1", `{"house_id":"a"}`)
+
+	if first.Code != http.StatusOK {
+		t.Errorf("first got %d, want %d", first.Code, http.StatusOK)
+	}
+	if inFlight.Code != http.StatusConflict {
+		t.Errorf("in-flight repeat got %d, want %d", inFlight.Code, http.StatusConflict)
+	}
+}
+
+func TestVerifyKeyReleaseAfterServerError(t *testing.T) {
+	calls := 0
+	router := newRouter(t, func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "database is down"})
+			return
+		}
+		util.SendSuccess(c, nil)
+	})
+
+	failed := send(router, "key-1", `{"house_id":"a"}`)
+	retry := send(router, "key-1", `{"house_id":"a"}`)
+
+	if failed.Code != http.StatusInternalServerError {
+		t.Errorf("first got %d, want %d", failed.Code, http.StatusInternalServerError)
+	}
+	if retry.Code != http.StatusOK || calls != 2 {
+		t.Errorf("retry got %d after %d calls, want %d after 2 calls", retry.Code, calls, http.StatusOK)
+	}
+	if retry.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("retry is replayed instead of run again")
+	}
+}
+
+func TestVerifyKeyBodyTooLarge(t *testing.T) {
+	calls := 0
+	router := newRouter(t, func(c *gin.Context) {
+		calls++
+		util.SendSuccess(c, nil)
+	})
+
+	res := send(router, "key-1", strings.Repeat("a", maxBodySize+1))
+
+	if res.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("got %d, want %d", res.Code, http.StatusRequestEntityTooLarge)
+	}
+	if calls != 0 {
+		t.Errorf("handler is called %d times, want 0", calls)
+	}
+}
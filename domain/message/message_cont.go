@@ -0,0 +1,370 @@
+package message
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	db "gubuk-service/db"
+	sqlc "gubuk-service/db/sqlc"
+	"gubuk-service/domain/house"
+	"gubuk-service/event"
+	"gubuk-service/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const defaultMessageLimit = 50
+
+// CreateConversation start a conversation between a tenant and an owner, optionally about a house or a transaction,
+// and send its first message. An existing conversation with the same participants & subject is reused.
+func CreateConversation(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+
+	userID, err := uuid.Parse(userPayload.UserID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	var req ConversationCreateRequest
+	err = c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	var participants sqlc.GetConversationByParticipantsParams
+	switch {
+	case req.TransactionID != "":
+		transactionID, _ := uuid.Parse(req.TransactionID)
+		transaction, err := db.Queries.GetTransactionById(context.TODO(), transactionID)
+		if err != nil {
+			util.SendBadRequest(c, errors.New("transaction with the provided id is not exist"))
+			return
+		}
+
+		if userID != transaction.TenantID && userID != transaction.OwnerID {
+			util.SendBadRequest(c, errors.New("you are not the tenant or the owner of this transaction"))
+			return
+		}
+
+		participants = sqlc.GetConversationByParticipantsParams{
+			TenantID:      transaction.TenantID,
+			OwnerID:       transaction.OwnerID,
+			HouseID:       uuid.NullUUID{UUID: transaction.HouseID, Valid: true},
+			TransactionID: uuid.NullUUID{UUID: transaction.ID, Valid: true},
+		}
+	case req.HouseID != "":
+		houseID, _ := uuid.Parse(req.HouseID)
+		houseRow, err := db.Queries.GetHouseById(context.TODO(), houseID)
+		if err != nil || !house.IsVisible(c, houseRow) {
+			util.SendBadRequest(c, errors.New("house with the provided id is not exist"))
+			return
+		}
+
+		if !userPayload.HasRole("tenant") || userID == houseRow.OwnerID {
+			util.SendBadRequest(c, errors.New("only a tenant could ask the owner about a house"))
+			return
+		}
+
+		participants = sqlc.GetConversationByParticipantsParams{
+			TenantID: userID,
+			OwnerID:  houseRow.OwnerID,
+			HouseID:  uuid.NullUUID{UUID: houseRow.ID, Valid: true},
+		}
+	case req.RecipientID != "":
+		recipientID, _ := uuid.Parse(req.RecipientID)
+		recipient, err := db.Queries.GetUserById(context.TODO(), recipientID)
+		if err != nil {
+			util.SendBadRequest(c, errors.New("user with the provided id is not exist"))
+			return
+		}
+
+		recipientIsOwner, err := db.Queries.HasUserRole(context.TODO(), sqlc.HasUserRoleParams{UserID: recipient.ID, RoleName: "owner"})
+		if err != nil {
+			util.SendServerError(c, err)
+			return
+		}
+		recipientIsTenant, err := db.Queries.HasUserRole(context.TODO(), sqlc.HasUserRoleParams{UserID: recipient.ID, RoleName: "tenant"})
+		if err != nil {
+			util.SendServerError(c, err)
+			return
+		}
+
+		switch {
+		case recipient.ID == userID:
+			util.SendBadRequest(c, errors.New("a conversation could not be held with yourself"))
+			return
+		case userPayload.HasRole("tenant") && recipientIsOwner:
+			participants = sqlc.GetConversationByParticipantsParams{TenantID: userID, OwnerID: recipient.ID}
+		case userPayload.HasRole("owner") && recipientIsTenant:
+			participants = sqlc.GetConversationByParticipantsParams{TenantID: recipient.ID, OwnerID: userID}
+		default:
+			util.SendBadRequest(c, errors.New("a conversation could only be held between a tenant and an owner"))
+			return
+		}
+	default:
+		util.SendBadRequest(c, errors.New("house_id, transaction_id or recipient_id is required"))
+		return
+	}
+
+	tx, err := db.DB.BeginTx(context.TODO(), nil)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer tx.Rollback()
+
+	// the participants are unique, so a conversation created by a concurrent request is reused instead
+	q := db.Queries.WithTx(tx)
+	conversation, err := q.CreateConversation(context.TODO(), sqlc.CreateConversationParams{
+		ID:            uuid.New(),
+		TenantID:      participants.TenantID,
+		OwnerID:       participants.OwnerID,
+		HouseID:       participants.HouseID,
+		TransactionID: participants.TransactionID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		conversation, err = q.GetConversationByParticipants(context.TODO(), participants)
+	}
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	newMessage, err := sendMessage(q, conversation.ID, userID, req.Body)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	publishMessageEvent(conversation, newMessage, userPayload.Username)
+
+	util.SendSuccess(c, gin.H{
+		"conversation_id": conversation.ID,
+		"message":         messageRowOf(newMessage),
+	})
+}
+
+// ListConversation return the conversations of the currently logged in user, the most recently active first
+func ListConversation(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+
+	userID, err := uuid.Parse(userPayload.UserID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	conversations, err := db.Queries.ListConversation(context.TODO(), userID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	if conversations == nil {
+		conversations = make([]sqlc.ListConversationRow, 0)
+	}
+
+	util.SendSuccess(c, conversations)
+}
+
+// ListMessage return a page of messages of a conversation, latest first
+func ListMessage(c *gin.Context) {
+	conversation, err := getConversation(c)
+	if err != nil {
+		util.SendNotFound(c, err)
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit <= 0 || limit > defaultMessageLimit {
+		limit = defaultMessageLimit
+	}
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	messages, err := db.Queries.ListConversationMessage(context.TODO(), sqlc.ListConversationMessageParams{
+		ConversationID: conversation.ID,
+		Limit:          int32(limit),
+		Offset:         int32(offset),
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	messageList := make([]MessageRow, 0, len(messages))
+	for _, v := range messages {
+		messageList = append(messageList, messageRowOf(v))
+	}
+
+	util.SendSuccess(c, messageList)
+}
+
+// SendMessage send a new message to a conversation
+func SendMessage(c *gin.Context) {
+	conversation, err := getConversation(c)
+	if err != nil {
+		util.SendNotFound(c, err)
+		return
+	}
+
+	var req MessageSendRequest
+	err = c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID, _ := uuid.Parse(userPayload.UserID)
+
+	tx, err := db.DB.BeginTx(context.TODO(), nil)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer tx.Rollback()
+
+	newMessage, err := sendMessage(db.Queries.WithTx(tx), conversation.ID, userID, req.Body)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	publishMessageEvent(conversation, newMessage, userPayload.Username)
+
+	util.SendSuccess(c, messageRowOf(newMessage))
+}
+
+// ReadConversation mark every message the other participant has sent to a conversation as read
+func ReadConversation(c *gin.Context) {
+	conversation, err := getConversation(c)
+	if err != nil {
+		util.SendNotFound(c, err)
+		return
+	}
+
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID, _ := uuid.Parse(userPayload.UserID)
+
+	readMessages, err := db.Queries.MarkConversationMessageRead(context.TODO(), sqlc.MarkConversationMessageReadParams{
+		ConversationID: conversation.ID,
+		SenderID:       userID,
+		ReadAt:         sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, gin.H{
+		"read_count": readMessages,
+	})
+}
+
+// sendMessage store a message & bump the last activity of its conversation
+func sendMessage(q *sqlc.Queries, conversationID uuid.UUID, senderID uuid.UUID, body string) (sqlc.Message, error) {
+	now := time.Now()
+	newMessage, err := q.CreateMessage(context.TODO(), sqlc.CreateMessageParams{
+		ID:             uuid.New(),
+		ConversationID: conversationID,
+		SenderID:       senderID,
+		Body:           body,
+		CreatedAt:      now,
+	})
+	if err != nil {
+		return sqlc.Message{}, err
+	}
+
+	err = q.UpdateConversationLastMessage(context.TODO(), sqlc.UpdateConversationLastMessageParams{
+		ID:            conversationID,
+		LastMessageAt: now,
+	})
+	if err != nil {
+		return sqlc.Message{}, err
+	}
+
+	return newMessage, nil
+}
+
+// publishMessageEvent tell the other participant of a conversation about a new message
+func publishMessageEvent(conversation sqlc.Conversation, m sqlc.Message, senderUsername string) {
+	recipientID := conversation.OwnerID
+	if m.SenderID == conversation.OwnerID {
+		recipientID = conversation.TenantID
+	}
+
+	event.Publish(event.Event{
+		Type:       event.MessageSent,
+		Recipients: []uuid.UUID{recipientID},
+		OwnerID:    conversation.OwnerID,
+		Message:    "New message from " + senderUsername,
+		Data: map[string]interface{}{
+			"conversation_id": conversation.ID,
+			"message_id":      m.ID,
+		},
+	})
+}
+
+// getConversation load the conversation from the :id param, making sure the caller takes part in it
+func getConversation(c *gin.Context) (sqlc.Conversation, error) {
+	errNotExist := errors.New("conversation with the provided id is not exist")
+
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return sqlc.Conversation{}, errNotExist
+	}
+
+	conversation, err := db.Queries.GetConversationById(context.TODO(), id)
+	if err != nil {
+		return sqlc.Conversation{}, errNotExist
+	}
+
+	if userID != conversation.TenantID.String() && userID != conversation.OwnerID.String() {
+		return sqlc.Conversation{}, errNotExist
+	}
+
+	return conversation, nil
+}
+
+func messageRowOf(m sqlc.Message) MessageRow {
+	row := MessageRow{
+		ID:        m.ID,
+		SenderID:  m.SenderID,
+		Body:      m.Body,
+		CreatedAt: m.CreatedAt,
+	}
+	if m.ReadAt.Valid {
+		readAt := m.ReadAt.Time
+		row.ReadAt = &readAt
+	}
+	return row
+}
@@ -0,0 +1,26 @@
+package message
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ConversationCreateRequest struct {
+	HouseID       string `form:"house_id" binding:"omitempty,uuid"`
+	TransactionID string `form:"transaction_id" binding:"omitempty,uuid"`
+	RecipientID   string `form:"recipient_id" binding:"omitempty,uuid"`
+	Body          string `form:"body" binding:"required,max=2000"`
+}
+
+type MessageSendRequest struct {
+	Body string `form:"body" binding:"required,max=2000"`
+}
+
+type MessageRow struct {
+	ID        uuid.UUID  `json:"id"`
+	SenderID  uuid.UUID  `json:"sender_id"`
+	Body      string     `json:"body"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
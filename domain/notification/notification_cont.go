@@ -0,0 +1,273 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"strconv"
+	"time"
+
+	db "gubuk-service/db"
+	sqlc "gubuk-service/db/sqlc"
+	"gubuk-service/event"
+	"gubuk-service/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultNotificationLimit = 20
+	streamHeartbeatInterval  = 30 * time.Second
+)
+
+func init() {
+	event.Subscribe(notify)
+}
+
+// notify store a notification of an event for each of it's recipients & push it to their open streams
+func notify(e event.Event) {
+	data, err := json.Marshal(e.Data)
+	if err != nil || e.Data == nil {
+		data = []byte("{}")
+	}
+
+	for _, userID := range e.Recipients {
+		newNotification, err := db.Queries.CreateNotification(context.TODO(), sqlc.CreateNotificationParams{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Type:      e.Type,
+			Message:   e.Message,
+			Data:      data,
+			CreatedAt: e.OccurredAt,
+		})
+		if err != nil {
+			log.Printf("couldn't store %s notification of user %s: %v", e.Type, userID, err)
+			continue
+		}
+
+		streamHub.push(userID, notificationRowOf(newNotification))
+	}
+}
+
+// ListNotification return a page of notifications of the currently logged in user, latest first
+func ListNotification(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+
+	userID, err := uuid.Parse(userPayload.UserID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit <= 0 || limit > defaultNotificationLimit {
+		limit = defaultNotificationLimit
+	}
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	notifications, err := db.Queries.ListNotification(context.TODO(), sqlc.ListNotificationParams{
+		UserID: userID,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	unreadCount, err := db.Queries.CountUnreadNotification(context.TODO(), userID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	notificationList := make([]NotificationRow, 0, len(notifications))
+	for _, v := range notifications {
+		notificationList = append(notificationList, notificationRowOf(v))
+	}
+
+	util.SendSuccess(c, gin.H{
+		"unread_count":  unreadCount,
+		"notifications": notificationList,
+	})
+}
+
+// ReadNotification mark a notification of the currently logged in user as read
+func ReadNotification(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+
+	userID, err := uuid.Parse(userPayload.UserID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.SendNotFound(c, errors.New("notification with the provided id is not exist"))
+		return
+	}
+
+	updatedRows, err := db.Queries.MarkNotificationRead(context.TODO(), sqlc.MarkNotificationReadParams{
+		ID:     id,
+		UserID: userID,
+		ReadAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	if updatedRows == 0 {
+		util.SendNotFound(c, errors.New("notification with the provided id is not exist"))
+		return
+	}
+
+	util.SendSuccess(c, nil)
+}
+
+// ReadAllNotification mark every notification of the currently logged in user as read
+func ReadAllNotification(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+
+	userID, err := uuid.Parse(userPayload.UserID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = db.Queries.MarkAllNotificationRead(context.TODO(), sqlc.MarkAllNotificationReadParams{
+		UserID: userID,
+		ReadAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, nil)
+}
+
+// StreamNotification push new notifications of the currently logged in user as Server-Sent Events,
+// it start with the number of unread notifications & keep the connection alive with a periodic ping
+func StreamNotification(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+
+	userID, err := uuid.Parse(userPayload.UserID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	unreadCount, err := db.Queries.CountUnreadNotification(context.TODO(), userID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	stream := streamHub.subscribe(userID)
+	defer streamHub.unsubscribe(userID, stream)
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.SSEvent("unread", gin.H{"unread_count": unreadCount})
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case n := <-stream:
+			c.SSEvent("notification", n)
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().Unix())
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// GetNotificationPreference return the notification preference of the currently logged in user
+func GetNotificationPreference(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+
+	userID, err := uuid.Parse(userPayload.UserID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	preference, err := getPreference(userID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, preference)
+}
+
+// UpdateNotificationPreference set the email language & which emails the currently logged in user receive
+func UpdateNotificationPreference(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+
+	userID, err := uuid.Parse(userPayload.UserID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	var req NotificationPreferenceRequest
+	err = c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	preference, err := db.Queries.UpsertNotificationPreference(context.TODO(), sqlc.UpsertNotificationPreferenceParams{
+		UserID:             userID,
+		Language:           req.Language,
+		EmailNewBooking:    *req.EmailNewBooking,
+		EmailPaymentProof:  *req.EmailPaymentProof,
+		EmailBookingStatus: *req.EmailBookingStatus,
+		UpdatedAt:          time.Now(),
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, preference)
+}
+
+func notificationRowOf(n sqlc.Notification) NotificationRow {
+	row := NotificationRow{
+		ID:        n.ID,
+		Type:      n.Type,
+		Message:   n.Message,
+		Data:      n.Data,
+		CreatedAt: n.CreatedAt,
+	}
+	if n.ReadAt.Valid {
+		readAt := n.ReadAt.Time
+		row.ReadAt = &readAt
+	}
+	return row
+}
@@ -0,0 +1,211 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	db "gubuk-service/db"
+	sqlc "gubuk-service/db/sqlc"
+	"gubuk-service/event"
+	"gubuk-service/mail"
+
+	"github.com/google/uuid"
+)
+
+// emailData is what the email templates are rendered with
+type emailData struct {
+	Name         string
+	Counterpart  string
+	HouseTitle   string
+	CheckIn      string
+	CheckOut     string
+	TotalPayment string
+	Reason       string
+	SearchName   string
+	Houses       []emailHouse
+}
+
+// emailHouse is a house listed in an email, e.g. the matches of a saved search
+type emailHouse struct {
+	Title string
+	Price string
+}
+
+func init() {
+	event.Subscribe(sendEmail)
+	event.Subscribe(sendSavedSearchEmail)
+}
+
+// sendEmail email the owner about a new booking or payment proof, and the tenant about
+// the approval, rejection, cancellation or expiry of their booking, as far as their preferences allow
+func sendEmail(e event.Event) {
+	if !mail.Enabled() {
+		return
+	}
+
+	transactionID, ok := e.Data["transaction_id"].(uuid.UUID)
+	if !ok {
+		return
+	}
+
+	transaction, err := db.Queries.GetTransactionById(context.TODO(), transactionID)
+	if err != nil {
+		log.Printf("couldn't get transaction %s to email: %v", transactionID, err)
+		return
+	}
+
+	var templateName string
+	var recipientID, counterpartID uuid.UUID
+	var allowed func(sqlc.NotificationPreference) bool
+	switch e.Type {
+	case event.TransactionCreated:
+		templateName = mail.TemplateNewBooking
+		allowed = func(p sqlc.NotificationPreference) bool { return p.EmailNewBooking }
+		recipientID, counterpartID = transaction.OwnerID, transaction.TenantID
+	case event.PaymentProofUploaded:
+		templateName = mail.TemplatePaymentProofUploaded
+		allowed = func(p sqlc.NotificationPreference) bool { return p.EmailPaymentProof }
+		recipientID, counterpartID = transaction.OwnerID, transaction.TenantID
+	case event.TransactionStatusChanged:
+		switch e.Data["payment_status"] {
+		case "approved":
+			templateName = mail.TemplateBookingApproved
+		case "rejected":
+			templateName = mail.TemplateBookingRejected
+		case "cancel":
+			templateName = mail.TemplateBookingCancelled
+			if e.Data["cancel_reason"] == event.CancelReasonExpired {
+				templateName = mail.TemplateBookingExpired
+			}
+		default:
+			return
+		}
+		allowed = func(p sqlc.NotificationPreference) bool { return p.EmailBookingStatus }
+		recipientID, counterpartID = transaction.TenantID, transaction.OwnerID
+	default:
+		return
+	}
+
+	preference, err := getPreference(recipientID)
+	if err != nil {
+		log.Printf("couldn't get notification preference of user %s: %v", recipientID, err)
+		return
+	}
+
+	if !allowed(preference) {
+		return
+	}
+
+	recipient, err := db.Queries.GetUserById(context.TODO(), recipientID)
+	if err != nil {
+		log.Printf("couldn't get user %s to email: %v", recipientID, err)
+		return
+	}
+
+	// a deleted user has no email anymore
+	if recipient.Email == "" {
+		return
+	}
+
+	data := emailData{
+		Name:         recipient.Fullname,
+		CheckIn:      transaction.CheckIn.Format("02 Jan 2006"),
+		CheckOut:     transaction.CheckOut.Format("02 Jan 2006"),
+		TotalPayment: mail.FormatRupiah(transaction.TotalPayment),
+	}
+	data.Reason, _ = e.Data["reason"].(string)
+
+	counterpart, err := db.Queries.GetUserById(context.TODO(), counterpartID)
+	if err == nil {
+		data.Counterpart = counterpart.Fullname
+	}
+
+	house, err := db.Queries.GetHouseById(context.TODO(), transaction.HouseID)
+	if err == nil {
+		data.HouseTitle = house.Title
+	}
+
+	message, err := mail.Render(recipient.Email, preference.Language, templateName, data)
+	if err != nil {
+		log.Printf("couldn't render %s email: %v", templateName, err)
+		return
+	}
+
+	err = mail.Send(message)
+	if err != nil {
+		log.Printf("couldn't send %s email to user %s: %v", templateName, recipientID, err)
+	}
+}
+
+// sendSavedSearchEmail email the user of a saved search alerting by email about it's newly matched houses
+func sendSavedSearchEmail(e event.Event) {
+	if e.Type != event.SavedSearchMatched || e.Data["channel"] != "email" || !mail.Enabled() {
+		return
+	}
+
+	recipientID, ok := e.Data["user_id"].(uuid.UUID)
+	if !ok {
+		return
+	}
+
+	preference, err := getPreference(recipientID)
+	if err != nil {
+		log.Printf("couldn't get notification preference of user %s: %v", recipientID, err)
+		return
+	}
+
+	recipient, err := db.Queries.GetUserById(context.TODO(), recipientID)
+	if err != nil {
+		log.Printf("couldn't get user %s to email: %v", recipientID, err)
+		return
+	}
+
+	if recipient.Email == "" {
+		return
+	}
+
+	data := emailData{
+		Name: recipient.Fullname,
+	}
+	data.SearchName, _ = e.Data["name"].(string)
+
+	houses, _ := e.Data["houses"].([]map[string]interface{})
+	for _, v := range houses {
+		title, _ := v["title"].(string)
+		price, _ := v["price"].(int64)
+		data.Houses = append(data.Houses, emailHouse{
+			Title: title,
+			Price: mail.FormatRupiah(price),
+		})
+	}
+
+	message, err := mail.Render(recipient.Email, preference.Language, mail.TemplateSavedSearchMatch, data)
+	if err != nil {
+		log.Printf("couldn't render %s email: %v", mail.TemplateSavedSearchMatch, err)
+		return
+	}
+
+	err = mail.Send(message)
+	if err != nil {
+		log.Printf("couldn't send %s email to user %s: %v", mail.TemplateSavedSearchMatch, recipientID, err)
+	}
+}
+
+// getPreference return the notification preference of a user, every email is turned on
+// in the default language for a user who never set it
+func getPreference(userID uuid.UUID) (sqlc.NotificationPreference, error) {
+	preference, err := db.Queries.GetNotificationPreference(context.TODO(), userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return sqlc.NotificationPreference{
+			UserID:             userID,
+			Language:           mail.Languages[0],
+			EmailNewBooking:    true,
+			EmailPaymentProof:  true,
+			EmailBookingStatus: true,
+		}, nil
+	}
+
+	return preference, err
+}
@@ -0,0 +1,54 @@
+package notification
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// hub fan out new notifications to the streams each user currently has open
+type hub struct {
+	mu      sync.Mutex
+	streams map[uuid.UUID]map[chan NotificationRow]struct{}
+}
+
+var streamHub = &hub{
+	streams: make(map[uuid.UUID]map[chan NotificationRow]struct{}),
+}
+
+func (h *hub) subscribe(userID uuid.UUID) chan NotificationRow {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stream := make(chan NotificationRow, 16)
+	if h.streams[userID] == nil {
+		h.streams[userID] = make(map[chan NotificationRow]struct{})
+	}
+	h.streams[userID][stream] = struct{}{}
+
+	return stream
+}
+
+func (h *hub) unsubscribe(userID uuid.UUID, stream chan NotificationRow) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.streams[userID], stream)
+	if len(h.streams[userID]) == 0 {
+		delete(h.streams, userID)
+	}
+}
+
+// push send a notification to every open stream of a user, a stream which couldn't keep up miss it
+// instead of blocking the others, it's still listed by ListNotification anyway
+func (h *hub) push(userID uuid.UUID, n NotificationRow) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for stream := range h.streams[userID] {
+		select {
+		case stream <- n:
+		default:
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package notification
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationPreferenceRequest struct {
+	Language           string `form:"language" json:"language" binding:"required,oneof=id en"`
+	EmailNewBooking    *bool  `form:"email_new_booking" json:"email_new_booking" binding:"required"`
+	EmailPaymentProof  *bool  `form:"email_payment_proof" json:"email_payment_proof" binding:"required"`
+	EmailBookingStatus *bool  `form:"email_booking_status" json:"email_booking_status" binding:"required"`
+}
+
+type NotificationRow struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	Message   string          `json:"message"`
+	Data      json.RawMessage `json:"data"`
+	ReadAt    *time.Time      `json:"read_at"`
+	CreatedAt time.Time       `json:"created_at"`
+}
@@ -0,0 +1,179 @@
+package review
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gubuk-service/util"
+	"time"
+
+	db "gubuk-service/db"
+	sqlc "gubuk-service/db/sqlc"
+	"gubuk-service/domain/house"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateReview let the tenant of an approved transaction review the house once the stay is over
+func CreateReview(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	transactionID := c.Param("id")
+	id, err := uuid.Parse(transactionID)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	var req ReviewCreateRequest
+	err = c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	reviewedTransaction, err := db.Queries.GetTransactionById(context.TODO(), id)
+	if err != nil {
+		util.SendBadRequest(c, errors.New("transaction with the provided id is not exist"))
+		return
+	}
+
+	if userID != reviewedTransaction.TenantID.String() {
+		util.SendBadRequest(c, errors.New("you are not the tenant of this transaction, you could not review it"))
+		return
+	}
+
+	if reviewedTransaction.PaymentStatus != "approved" || reviewedTransaction.CheckOut.After(time.Now()) {
+		util.SendBadRequest(c, errors.New("only a completed stay could be reviewed"))
+		return
+	}
+
+	errAlreadyReviewed := errors.New("this stay is already reviewed")
+	_, err = db.Queries.GetReviewByTransactionId(context.TODO(), id)
+	if err == nil {
+		util.SendConflict(c, errAlreadyReviewed)
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		util.SendServerError(c, err)
+		return
+	}
+
+	newReview, err := db.Queries.CreateReview(context.TODO(), sqlc.CreateReviewParams{
+		ID:            uuid.New(),
+		TransactionID: id,
+		HouseID:       reviewedTransaction.HouseID,
+		TenantID:      reviewedTransaction.TenantID,
+		OwnerID:       reviewedTransaction.OwnerID,
+		Rating:        int32(req.Rating),
+		Comment:       req.Comment,
+	})
+	if err != nil {
+		// the stay is reviewed by a concurrent request since it's checked above
+		if util.IsUniqueViolation(err, "reviews_transaction_id_key") {
+			util.SendConflict(c, errAlreadyReviewed)
+			return
+		}
+
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, gin.H{
+		"created_id": newReview.ID,
+	})
+}
+
+// ReplyReview let the owner post a single public reply to a review of their house
+func ReplyReview(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	reviewID := c.Param("id")
+	id, err := uuid.Parse(reviewID)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	var req ReviewReplyRequest
+	err = c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	repliedReview, err := db.Queries.GetReviewById(context.TODO(), id)
+	if err != nil {
+		util.SendNotFound(c, errors.New("review with the provided id is not exist"))
+		return
+	}
+
+	if userID != repliedReview.OwnerID.String() {
+		util.SendBadRequest(c, errors.New("you are not own the reviewed house, you could not reply it"))
+		return
+	}
+
+	updatedRows, err := db.Queries.UpdateReviewReply(context.TODO(), sqlc.UpdateReviewReplyParams{
+		ID:         id,
+		OwnerReply: req.Reply,
+		RepliedAt:  sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	if updatedRows == 0 {
+		util.SendBadRequest(c, errors.New("this review is already replied"))
+		return
+	}
+
+	util.SendSuccess(c, nil)
+}
+
+// ListHouseReview return the reviews of a house, latest first
+func ListHouseReview(c *gin.Context) {
+	houseID := c.Param("id")
+	id, err := uuid.Parse(houseID)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	reviewedHouse, err := db.Queries.GetHouseById(context.TODO(), id)
+	if err != nil || !house.IsVisible(c, reviewedHouse) {
+		util.SendNotFound(c, errors.New("house with the provided id is not exist"))
+		return
+	}
+
+	reviews, err := db.Queries.ListHouseReview(context.TODO(), id)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	reviewList := make([]ReviewRow, 0, len(reviews))
+	for _, v := range reviews {
+		row := ReviewRow{
+			ID:             v.ID,
+			Rating:         v.Rating,
+			Comment:        v.Comment,
+			OwnerReply:     v.OwnerReply,
+			CreatedAt:      v.CreatedAt,
+			TenantFullname: v.TenantFullname,
+			TenantAvatar:   v.TenantAvatar,
+		}
+		if v.RepliedAt.Valid {
+			repliedAt := v.RepliedAt.Time
+			row.RepliedAt = &repliedAt
+		}
+		reviewList = append(reviewList, row)
+	}
+
+	util.SendSuccess(c, reviewList)
+}
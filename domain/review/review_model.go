@@ -0,0 +1,27 @@
+package review
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReviewCreateRequest struct {
+	Rating  int    `form:"rating" binding:"required,min=1,max=5"`
+	Comment string `form:"comment" binding:"required,max=2000"`
+}
+
+type ReviewReplyRequest struct {
+	Reply string `form:"reply" binding:"required,max=2000"`
+}
+
+type ReviewRow struct {
+	ID             uuid.UUID  `json:"id"`
+	Rating         int32      `json:"rating"`
+	Comment        string     `json:"comment"`
+	OwnerReply     string     `json:"owner_reply"`
+	RepliedAt      *time.Time `json:"replied_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	TenantFullname string     `json:"tenant_fullname"`
+	TenantAvatar   string     `json:"tenant_avatar"`
+}
@@ -2,9 +2,16 @@ package transaction
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"gubuk-service/domain/user"
+	"gubuk-service/event"
 	"gubuk-service/media"
+	"gubuk-service/payment"
+	"gubuk-service/pricing"
 	"gubuk-service/util"
+	"log"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -48,61 +55,127 @@ func CreateTransaction(c *gin.Context) {
 		return
 	}
 
-	var checkOut time.Time
-	switch house.TypeRent {
-	case "day":
-		checkOut = req.CheckIn.AddDate(0, 0, req.TimeRent)
-	case "month":
-		checkOut = req.CheckIn.AddDate(0, req.TimeRent, 0)
-	case "year":
-		checkOut = req.CheckIn.AddDate(req.TimeRent, 0, 0)
+	if house.Status != "published" {
+		util.SendBadRequest(c, errors.New("house is not available to be booked"))
+		return
+	}
+
+	if house.OwnerID == tenantID {
+		util.SendBadRequest(c, errors.New("you could not book your own house"))
+		return
 	}
 
-	newTransaction, err := db.Queries.CreateTransaction(context.TODO(), sqlc.CreateTransactionParams{
+	rates, err := pricing.LoadRates(house, req.RentalPlanID)
+	if err != nil {
+		if errors.Is(err, pricing.ErrRentalPlanNotFound) {
+			util.SendBadRequest(c, err)
+			return
+		}
+
+		util.SendServerError(c, err)
+		return
+	}
+
+	quote, err := pricing.Calculate(rates, req.CheckIn, req.TimeRent)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	tx, err := db.DB.BeginTx(context.TODO(), nil)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := db.Queries.WithTx(tx)
+
+	newTransaction, err := qtx.CreateTransaction(context.TODO(), sqlc.CreateTransactionParams{
 		ID:            uuid.New(),
 		TenantID:      tenantID,
 		OwnerID:       house.OwnerID,
 		HouseID:       houseID,
 		PaymentStatus: "waiting-payment",
 		PaymentProof:  "",
-		TotalPayment:  int64(req.TimeRent) * house.Price,
-		CheckIn:       req.CheckIn,
-		CheckOut:      checkOut,
+		TotalPayment:  quote.Total,
+		CheckIn:       quote.CheckIn,
+		CheckOut:      quote.CheckOut,
 		TimeRent:      strconv.Itoa(req.TimeRent),
+		RentalUnit:    rates.TypeRent,
 	})
 	if err != nil {
 		util.SendServerError(c, err)
 		return
 	}
 
-	util.SendSuccess(c, newTransaction)
+	lineItems, err := createLineItems(qtx, newTransaction.ID, quote.LineItems)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	event.Publish(transactionEvent(event.TransactionCreated, newTransaction, "New booking of "+house.Title, newTransaction.OwnerID))
+
+	util.SendSuccess(c, TransactionDetail{
+		Transaction: newTransaction,
+		LineItems:   lineItems,
+	})
 }
 
-func ListTransaction(c *gin.Context) {
+// GetTransactionDetail return a transaction with it's price breakdown to it's tenant or owner
+func GetTransactionDetail(c *gin.Context) {
 	payload, _ := c.Get("user")
 	userPayload, _ := payload.(*util.UserPayload)
 	userID := userPayload.UserID
-	userRole := userPayload.UserRole
 
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	listTransactionQueryBuilder := psql.Select("transactions.id", "tenant.fullname AS tenant_fullname", "tenant.gender AS tenant_gender", "tenant.phone_number AS tenant_phone_number", "house.title AS house_title", "house.province_id AS house_province_id", "house.city_id AS house_city_id", "house.amenities AS house_amenities", "house.type_rent AS house_type_rent", "payment_status", "payment_proof", "total_payment", "check_in", "check_out", "time_rent", "transactions.created_at", "transactions.updated_at").From("transactions").Join("users AS tenant ON tenant.id = transactions.tenant_id").Join("homes AS house ON house.id = transactions.house_id")
+	transactionID := c.Param("id")
+	id, err := uuid.Parse(transactionID)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
 
-	if userRole == "tenant" {
-		listTransactionQueryBuilder = listTransactionQueryBuilder.Where(sq.Eq{"tenant_id": userID})
-	} else if userRole == "owner" {
-		listTransactionQueryBuilder = listTransactionQueryBuilder.Where(sq.Eq{"transactions.owner_id": userID})
+	transaction, err := db.Queries.GetTransactionById(context.TODO(), id)
+	if err != nil {
+		util.SendNotFound(c, errors.New("transaction with the provided id is not exist"))
+		return
 	}
 
-	statusFilter := c.Query("status")
-	if statusFilter != "" {
-		orQuery := sq.Or{}
-		for _, v := range strings.Split(statusFilter, ",") {
-			orQuery = append(orQuery, sq.Eq{"payment_status": v})
-		}
-		listTransactionQueryBuilder = listTransactionQueryBuilder.Where(orQuery)
+	if !userPayload.HasPermission(user.PermissionTransactionModerate) && userID != transaction.TenantID.String() && userID != transaction.OwnerID.String() {
+		util.SendUnauthorized(c, errors.New("you could not access this transaction"))
+		return
 	}
 
-	listTransactionQuery, args, err := listTransactionQueryBuilder.ToSql()
+	lineItems, err := db.Queries.ListTransactionLineItem(context.TODO(), id)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	if lineItems == nil {
+		lineItems = make([]sqlc.TransactionLineItem, 0)
+	}
+
+	if transaction.PaymentProof != "" {
+		transaction.PaymentProof = paymentProofPath(id)
+	}
+
+	util.SendSuccess(c, TransactionDetail{
+		Transaction: transaction,
+		LineItems:   lineItems,
+	})
+}
+
+func ListTransaction(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+
+	listTransactionQuery, args, err := listTransactionQueryBuilderOf(c, userPayload).ToSql()
 	if err != nil {
 		util.SendServerError(c, err)
 		return
@@ -116,26 +189,8 @@ func ListTransaction(c *gin.Context) {
 	defer rows.Close()
 	transactionList := make([]TransactionListRow, 0)
 	for rows.Next() {
-		var i TransactionListRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.TenantFullname,
-			&i.TenantGender,
-			&i.TenantPhoneNumber,
-			&i.HouseTitle,
-			&i.HouseProvinceID,
-			&i.HouseCityID,
-			&i.HouseAmenities,
-			&i.HouseTypeRent,
-			&i.PaymentStatus,
-			&i.PaymentProof,
-			&i.TotalPayment,
-			&i.CheckIn,
-			&i.CheckOut,
-			&i.TimeRent,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
+		i, err := scanTransactionListRow(rows)
+		if err != nil {
 			util.SendServerError(c, err)
 			return
 		}
@@ -153,7 +208,13 @@ func ListTransaction(c *gin.Context) {
 	util.SendSuccess(c, transactionList)
 }
 
+// PayTransaction submit a payment proof of a transaction, tenant could resubmit
+// it when the previous submission was rejected
 func PayTransaction(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
 	transactionID := c.Param("id")
 	id, err := uuid.Parse(transactionID)
 	if err != nil {
@@ -161,6 +222,29 @@ func PayTransaction(c *gin.Context) {
 		return
 	}
 
+	var req PaymentSubmissionCreateRequest
+	err = c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	paidTransaction, err := db.Queries.GetTransactionById(context.TODO(), id)
+	if err != nil {
+		util.SendBadRequest(c, errors.New("transaction with the provided id is not exist"))
+		return
+	}
+
+	if userID != paidTransaction.TenantID.String() {
+		util.SendUnauthorized(c, errors.New("you are not the tenant of this transaction, you could not pay it"))
+		return
+	}
+
+	if paidTransaction.PaymentStatus != "waiting-payment" && paidTransaction.PaymentStatus != "rejected" {
+		util.SendBadRequest(c, errors.New("transaction is not waiting for a payment"))
+		return
+	}
+
 	paymentProof, err := c.FormFile("payment_proof")
 	if err != nil {
 		util.SendBadRequest(c, err)
@@ -173,13 +257,44 @@ func PayTransaction(c *gin.Context) {
 		return
 	}
 
-	newPaymentProof, err := media.UploadMedia("transaction", paymentProof)
+	// the amount & transfer date are assumed to follow the bill when tenant doesn't fill it
+	if req.Amount == 0 {
+		req.Amount = paidTransaction.TotalPayment
+	}
+	if req.TransferDate.IsZero() {
+		req.TransferDate = time.Now()
+	}
+
+	newPaymentProof, err := media.UploadPrivateMedia("transaction", paymentProof)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	tx, err := db.DB.BeginTx(context.TODO(), nil)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := db.Queries.WithTx(tx)
+
+	newSubmission, err := qtx.CreatePaymentSubmission(context.TODO(), sqlc.CreatePaymentSubmissionParams{
+		ID:            uuid.New(),
+		TransactionID: id,
+		PaymentProof:  newPaymentProof,
+		Amount:        req.Amount,
+		TransferDate:  req.TransferDate,
+		PaymentMethod: req.PaymentMethod,
+		Notes:         req.Notes,
+		Status:        "waiting-approve",
+	})
 	if err != nil {
 		util.SendServerError(c, err)
 		return
 	}
 
-	err = db.Queries.UpdateTransactionPaymentProofById(context.TODO(), sqlc.UpdateTransactionPaymentProofByIdParams{
+	err = qtx.UpdateTransactionPaymentProofById(context.TODO(), sqlc.UpdateTransactionPaymentProofByIdParams{
 		ID:            id,
 		PaymentStatus: "waiting-approve",
 		PaymentProof:  newPaymentProof,
@@ -187,14 +302,442 @@ func PayTransaction(c *gin.Context) {
 	})
 	if err != nil {
 		util.SendServerError(c, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
 	}
 
+	paidTransaction.PaymentStatus = "waiting-approve"
+	event.Publish(transactionEvent(event.PaymentProofUploaded, paidTransaction, "A payment proof is waiting for your approval", paidTransaction.OwnerID))
+
+	newSubmission.PaymentProof = submissionPaymentProofPath(id, newSubmission.ID)
 	util.SendSuccess(c, gin.H{
-		"new_image": newPaymentProof,
+		"new_image":  paymentProofPath(id),
+		"submission": newSubmission,
+	})
+}
+
+// GetTransactionPaymentProof redirect the tenant or the owner of a transaction
+// to a short-lived url of it's latest payment proof
+func GetTransactionPaymentProof(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	transactionID := c.Param("id")
+	id, err := uuid.Parse(transactionID)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	transaction, err := db.Queries.GetTransactionById(context.TODO(), id)
+	if err != nil {
+		util.SendNotFound(c, errors.New("transaction with the provided id is not exist"))
+		return
+	}
+
+	if !userPayload.HasPermission(user.PermissionTransactionModerate) && userID != transaction.TenantID.String() && userID != transaction.OwnerID.String() {
+		util.SendUnauthorized(c, errors.New("you could not access the payment proof of this transaction"))
+		return
+	}
+
+	if transaction.PaymentProof == "" {
+		util.SendNotFound(c, errors.New("transaction has no payment proof yet"))
+		return
+	}
+
+	redirectToPaymentProof(c, transaction.PaymentProof)
+}
+
+// ListPaymentSubmission return every payment proof submitted for a transaction, latest first
+func ListPaymentSubmission(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	transactionID := c.Param("id")
+	id, err := uuid.Parse(transactionID)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	transaction, err := db.Queries.GetTransactionById(context.TODO(), id)
+	if err != nil {
+		util.SendNotFound(c, errors.New("transaction with the provided id is not exist"))
+		return
+	}
+
+	if !userPayload.HasPermission(user.PermissionTransactionModerate) && userID != transaction.TenantID.String() && userID != transaction.OwnerID.String() {
+		util.SendUnauthorized(c, errors.New("you could not access the payment submissions of this transaction"))
+		return
+	}
+
+	submissionList, err := db.Queries.ListPaymentSubmission(context.TODO(), id)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	for i := range submissionList {
+		if submissionList[i].PaymentProof != "" {
+			submissionList[i].PaymentProof = submissionPaymentProofPath(id, submissionList[i].ID)
+		}
+	}
+	if submissionList == nil {
+		submissionList = make([]sqlc.PaymentSubmission, 0)
+	}
+
+	util.SendSuccess(c, submissionList)
+}
+
+// GetPaymentSubmissionProof redirect the tenant or the owner of a transaction
+// to a short-lived url of the payment proof of one of it's submissions
+func GetPaymentSubmissionProof(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	submission, transaction, err := getPaymentSubmission(c)
+	if err != nil {
+		util.SendNotFound(c, err)
+		return
+	}
+
+	if !userPayload.HasPermission(user.PermissionTransactionModerate) && userID != transaction.TenantID.String() && userID != transaction.OwnerID.String() {
+		util.SendUnauthorized(c, errors.New("you could not access the payment proof of this transaction"))
+		return
+	}
+
+	if submission.PaymentProof == "" {
+		util.SendNotFound(c, errors.New("payment submission has no payment proof"))
+		return
+	}
+
+	redirectToPaymentProof(c, submission.PaymentProof)
+}
+
+// RejectPaymentSubmission reject a payment submission with a reason, so the tenant could resubmit it
+func RejectPaymentSubmission(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	var req PaymentSubmissionRejectRequest
+	err := c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	submission, transaction, err := getPaymentSubmission(c)
+	if err != nil {
+		util.SendNotFound(c, err)
+		return
+	}
+
+	if userID != transaction.OwnerID.String() {
+		util.SendBadRequest(c, errors.New("you are not own this transaction, you could not reject it's payment"))
+		return
+	}
+
+	if submission.Status != "waiting-approve" {
+		util.SendBadRequest(c, errors.New("payment submission is already "+submission.Status))
+		return
+	}
+
+	tx, err := db.DB.BeginTx(context.TODO(), nil)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := db.Queries.WithTx(tx)
+
+	err = qtx.UpdatePaymentSubmissionStatusById(context.TODO(), sqlc.UpdatePaymentSubmissionStatusByIdParams{
+		ID:              submission.ID,
+		Status:          "rejected",
+		RejectionReason: req.Reason,
+		UpdatedAt:       time.Now(),
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = syncTransactionStatus(qtx, transaction.ID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	transaction.PaymentStatus = "rejected"
+	rejectedEvent := transactionEvent(event.TransactionStatusChanged, transaction, "Your payment proof is rejected: "+req.Reason, transaction.TenantID)
+	rejectedEvent.Data["reason"] = req.Reason
+	event.Publish(rejectedEvent)
+
+	util.SendSuccess(c, nil)
+}
+
+// CreateTransactionCharge create a charge of a transaction on the payment gateway,
+// the tenant complete the payment on the returned redirect url
+func CreateTransactionCharge(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	if !payment.Enabled() {
+		util.SendBadRequest(c, payment.ErrDisabled)
+		return
+	}
+
+	transactionID := c.Param("id")
+	id, err := uuid.Parse(transactionID)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	chargedTransaction, err := db.Queries.GetTransactionById(context.TODO(), id)
+	if err != nil {
+		util.SendBadRequest(c, errors.New("transaction with the provided id is not exist"))
+		return
+	}
+
+	if userID != chargedTransaction.TenantID.String() {
+		util.SendUnauthorized(c, errors.New("you are not the tenant of this transaction, you could not pay it"))
+		return
+	}
+
+	if chargedTransaction.PaymentStatus != "waiting-payment" && chargedTransaction.PaymentStatus != "rejected" {
+		util.SendBadRequest(c, errors.New("transaction is not waiting for a payment"))
+		return
+	}
+
+	tenant, err := db.Queries.GetUserById(context.TODO(), chargedTransaction.TenantID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	house, err := db.Queries.GetHouseById(context.TODO(), chargedTransaction.HouseID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	chargeID := uuid.New()
+	chargeResult, err := payment.CreateCharge(payment.Charge{
+		ID:            chargeID.String(),
+		Amount:        chargedTransaction.TotalPayment,
+		Description:   house.Title,
+		CustomerName:  tenant.Fullname,
+		CustomerEmail: tenant.Email,
+		CustomerPhone: tenant.PhoneNumber,
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	newCharge, err := db.Queries.CreatePaymentCharge(context.TODO(), sqlc.CreatePaymentChargeParams{
+		ID:            chargeID,
+		TransactionID: id,
+		Provider:      payment.ProviderName(),
+		ProviderRef:   chargeResult.ProviderRef,
+		Amount:        chargedTransaction.TotalPayment,
+		Status:        payment.StatusPending,
+		RedirectUrl:   chargeResult.RedirectURL,
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, newCharge)
+}
+
+// HandlePaymentWebhook receive a charge status notification from the payment gateway,
+// a paid charge approve it's transaction once no matter how many times it's notified
+func HandlePaymentWebhook(c *gin.Context) {
+	notification, err := payment.ParseNotification(c.Request)
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			util.SendUnauthorized(c, err)
+			return
+		}
+
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	chargeID, err := uuid.Parse(notification.ChargeID)
+	if err != nil {
+		util.SendNotFound(c, errors.New("charge with the provided id is not exist"))
+		return
+	}
+
+	charge, err := db.Queries.GetPaymentChargeById(context.TODO(), chargeID)
+	if err != nil {
+		util.SendNotFound(c, errors.New("charge with the provided id is not exist"))
+		return
+	}
+
+	if notification.Amount != charge.Amount {
+		util.SendBadRequest(c, errors.New("notified amount doesn't match the charge"))
+		return
+	}
+
+	if notification.Status == payment.StatusPending {
+		util.SendSuccess(c, nil)
+		return
+	}
+
+	tx, err := db.DB.BeginTx(context.TODO(), nil)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := db.Queries.WithTx(tx)
+
+	updatedRows, err := qtx.UpdatePendingPaymentChargeStatus(context.TODO(), sqlc.UpdatePendingPaymentChargeStatusParams{
+		ID:        chargeID,
+		Status:    notification.Status,
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	// the charge was already settled by a previous notification
+	if updatedRows == 0 {
+		util.SendSuccess(c, nil)
+		return
+	}
+
+	// an expired charge expire it's booking too, unless it's already paid some other way
+	expired := false
+	if notification.Status == payment.StatusExpired {
+		expiredRows, err := qtx.ExpireTransactionById(context.TODO(), sqlc.ExpireTransactionByIdParams{
+			ID:        charge.TransactionID,
+			UpdatedAt: time.Now(),
+		})
+		if err != nil {
+			util.SendServerError(c, err)
+			return
+		}
+		expired = expiredRows > 0
+	}
+
+	// a payment only approves a booking which is still open, a booking already cancelled, expired or
+	// paid some other way keeps it's status & the payment is left to be refunded
+	var paidTransaction sqlc.Transaction
+	refundDue := false
+	if notification.Status == payment.StatusPaid {
+		approvedRows, err := qtx.UpdateOpenTransactionStatus(context.TODO(), sqlc.UpdateOpenTransactionStatusParams{
+			ID:            charge.TransactionID,
+			PaymentStatus: "approved",
+			UpdatedAt:     time.Now(),
+		})
+		if err != nil {
+			util.SendServerError(c, err)
+			return
+		}
+
+		if approvedRows == 0 {
+			refundDue = true
+			err = qtx.UpdatePaymentChargeStatus(context.TODO(), sqlc.UpdatePaymentChargeStatusParams{
+				ID:        chargeID,
+				Status:    chargeRefundDue,
+				UpdatedAt: time.Now(),
+			})
+		} else {
+			paidTransaction, err = approvePaidCharge(qtx, charge, notification)
+		}
+		if err != nil {
+			util.SendServerError(c, err)
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	if refundDue {
+		log.Printf("charge %s is paid after it's transaction %s is settled, it should be refunded", chargeID, charge.TransactionID)
+	}
+
+	if notification.Status == payment.StatusPaid && !refundDue {
+		event.Publish(transactionEvent(event.TransactionStatusChanged, paidTransaction, "Payment is received through "+charge.Provider, paidTransaction.TenantID, paidTransaction.OwnerID))
+	}
+
+	if expired {
+		expiredTransaction, err := db.Queries.GetTransactionById(context.TODO(), charge.TransactionID)
+		if err != nil {
+			log.Printf("couldn't get expired transaction %s: %v", charge.TransactionID, err)
+		} else {
+			event.Publish(expiredEvent(expiredTransaction, "the "+charge.Provider+" payment is expired"))
+		}
+	}
+
+	util.SendSuccess(c, nil)
+}
+
+// approvePaidCharge record the payment of a charge as an approved submission, a manual submission still
+// waiting for approval is rejected as it's not needed anymore
+func approvePaidCharge(q *sqlc.Queries, charge sqlc.PaymentCharge, notification payment.Notification) (sqlc.Transaction, error) {
+	err := q.RejectWaitingPaymentSubmission(context.TODO(), sqlc.RejectWaitingPaymentSubmissionParams{
+		TransactionID:   charge.TransactionID,
+		RejectionReason: "the booking is paid through " + charge.Provider,
+		UpdatedAt:       time.Now(),
+	})
+	if err != nil {
+		return sqlc.Transaction{}, err
+	}
+
+	_, err = q.CreatePaymentSubmission(context.TODO(), sqlc.CreatePaymentSubmissionParams{
+		ID:            uuid.New(),
+		TransactionID: charge.TransactionID,
+		PaymentProof:  "",
+		Amount:        notification.Amount,
+		TransferDate:  time.Now(),
+		PaymentMethod: charge.Provider,
+		Notes:         notification.ProviderRef,
+		Status:        "approved",
 	})
+	if err != nil {
+		return sqlc.Transaction{}, err
+	}
+
+	err = syncTransactionStatus(q, charge.TransactionID)
+	if err != nil {
+		return sqlc.Transaction{}, err
+	}
+
+	return q.GetTransactionById(context.TODO(), charge.TransactionID)
 }
 
 func UpdateTransactionStatus(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
 	transactionID := c.Param("id")
 	id, err := uuid.Parse(transactionID)
 	if err != nil {
@@ -202,13 +745,36 @@ func UpdateTransactionStatus(c *gin.Context) {
 		return
 	}
 
-	status := c.Query("status")
-	if status == "" {
-		util.SendBadRequest(c, errors.New("status query is required"))
+	var req TransactionStatusUpdateRequest
+	err = c.BindQuery(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
 		return
 	}
+	status := req.Status
 
-	err = db.Queries.UpdateTransactionStatusById(context.TODO(), sqlc.UpdateTransactionStatusByIdParams{
+	tx, err := db.DB.BeginTx(context.TODO(), nil)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := db.Queries.WithTx(tx)
+
+	updatedTransaction, err := qtx.GetTransactionById(context.TODO(), id)
+	if err != nil {
+		util.SendBadRequest(c, errors.New("transaction with the provided id is not exist"))
+		return
+	}
+
+	if userID != updatedTransaction.OwnerID.String() {
+		util.SendBadRequest(c, errors.New("you are not own this transaction, you could not update it"))
+		return
+	}
+
+	// only a booking which is still open is approved or cancelled, the status is checked by the update itself
+	// so a booking paid or expired in the meantime isn't overwritten
+	updatedRows, err := qtx.UpdateOpenTransactionStatus(context.TODO(), sqlc.UpdateOpenTransactionStatusParams{
 		ID:            id,
 		PaymentStatus: status,
 		UpdatedAt:     time.Now(),
@@ -217,6 +783,224 @@ func UpdateTransactionStatus(c *gin.Context) {
 		util.SendServerError(c, err)
 		return
 	}
+	if updatedRows == 0 {
+		util.SendConflict(c, errors.New("transaction is no longer waiting for a payment or an approval, it's status could not be changed"))
+		return
+	}
+
+	// approving a transaction is approving it's latest payment submission
+	if status == "approved" {
+		var latestSubmission sqlc.PaymentSubmission
+		latestSubmission, err = qtx.GetLatestPaymentSubmission(context.TODO(), id)
+		if err != nil || latestSubmission.Status != "waiting-approve" {
+			util.SendBadRequest(c, errors.New("transaction has no payment submission waiting to be approved"))
+			return
+		}
+
+		err = qtx.UpdatePaymentSubmissionStatusById(context.TODO(), sqlc.UpdatePaymentSubmissionStatusByIdParams{
+			ID:        latestSubmission.ID,
+			Status:    "approved",
+			UpdatedAt: time.Now(),
+		})
+		if err != nil {
+			util.SendServerError(c, err)
+			return
+		}
+
+		err = syncTransactionStatus(qtx, id)
+	} else {
+		// a proof waiting for approval is settled along with it's cancelled booking
+		err = qtx.RejectWaitingPaymentSubmission(context.TODO(), sqlc.RejectWaitingPaymentSubmissionParams{
+			TransactionID:   id,
+			RejectionReason: "the booking is cancelled by the owner",
+			UpdatedAt:       time.Now(),
+		})
+	}
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	updatedTransaction.PaymentStatus = status
+	statusChanged := transactionEvent(event.TransactionStatusChanged, updatedTransaction, "Your booking is "+status, updatedTransaction.TenantID)
+	if status == "cancel" {
+		statusChanged.Data["cancel_reason"] = event.CancelReasonOwner
+	}
+	event.Publish(statusChanged)
 
 	util.SendSuccess(c, nil)
 }
+
+// transactionEvent describe something happened to a transaction to be told to the recipients
+func transactionEvent(eventType string, transaction sqlc.Transaction, message string, recipients ...uuid.UUID) event.Event {
+	return event.Event{
+		Type:       eventType,
+		Recipients: recipients,
+		OwnerID:    transaction.OwnerID,
+		Message:    message,
+		Data: map[string]interface{}{
+			"transaction_id": transaction.ID,
+			"house_id":       transaction.HouseID,
+			"payment_status": transaction.PaymentStatus,
+		},
+	}
+}
+
+// createLineItems store the price breakdown of a transaction
+func createLineItems(q *sqlc.Queries, transactionID uuid.UUID, lineItems []pricing.LineItem) ([]sqlc.TransactionLineItem, error) {
+	createdLineItems := make([]sqlc.TransactionLineItem, 0, len(lineItems))
+	for i, v := range lineItems {
+		lineItem, err := q.CreateTransactionLineItem(context.TODO(), sqlc.CreateTransactionLineItemParams{
+			ID:            uuid.New(),
+			TransactionID: transactionID,
+			Kind:          v.Kind,
+			Description:   v.Description,
+			Quantity:      v.Quantity,
+			UnitPrice:     v.UnitPrice,
+			Amount:        v.Amount,
+			Position:      int32(i),
+		})
+		if err != nil {
+			return nil, err
+		}
+		createdLineItems = append(createdLineItems, lineItem)
+	}
+
+	return createdLineItems, nil
+}
+
+// syncTransactionStatus derive the payment status of a transaction from it's latest payment submission
+func syncTransactionStatus(q *sqlc.Queries, transactionID uuid.UUID) error {
+	latestSubmission, err := q.GetLatestPaymentSubmission(context.TODO(), transactionID)
+	if err != nil {
+		return err
+	}
+
+	return q.UpdateTransactionPaymentProofById(context.TODO(), sqlc.UpdateTransactionPaymentProofByIdParams{
+		ID:            transactionID,
+		PaymentStatus: latestSubmission.Status,
+		PaymentProof:  latestSubmission.PaymentProof,
+		UpdatedAt:     time.Now(),
+	})
+}
+
+// getPaymentSubmission return the payment submission & the transaction referred by the id & submission_id params
+func getPaymentSubmission(c *gin.Context) (sqlc.PaymentSubmission, sqlc.Transaction, error) {
+	errNotExist := errors.New("payment submission with the provided id is not exist")
+
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return sqlc.PaymentSubmission{}, sqlc.Transaction{}, errNotExist
+	}
+
+	submissionID, err := uuid.Parse(c.Param("submission_id"))
+	if err != nil {
+		return sqlc.PaymentSubmission{}, sqlc.Transaction{}, errNotExist
+	}
+
+	submission, err := db.Queries.GetPaymentSubmissionById(context.TODO(), submissionID)
+	if err != nil || submission.TransactionID != transactionID {
+		return sqlc.PaymentSubmission{}, sqlc.Transaction{}, errNotExist
+	}
+
+	transaction, err := db.Queries.GetTransactionById(context.TODO(), transactionID)
+	if err != nil {
+		return sqlc.PaymentSubmission{}, sqlc.Transaction{}, errNotExist
+	}
+
+	return submission, transaction, nil
+}
+
+// redirectToPaymentProof redirect to a short-lived url of a private payment proof
+func redirectToPaymentProof(c *gin.Context, paymentProof string) {
+	paymentProofUrl, err := media.SignPrivateMedia(paymentProof)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, paymentProofUrl)
+}
+
+// paymentProofPath return the api path serving the latest payment proof of a transaction
+func paymentProofPath(id uuid.UUID) string {
+	return "/api/transactions/" + id.String() + "/payment-proof"
+}
+
+// submissionPaymentProofPath return the api path serving the payment proof of a payment submission
+func submissionPaymentProofPath(id uuid.UUID, submissionID uuid.UUID) string {
+	return "/api/transactions/" + id.String() + "/payment-submissions/" + submissionID.String() + "/payment-proof"
+}
+
+// listTransactionQueryBuilderOf build the query of the transactions the user could list,
+// narrowed down by the as & status query params
+func listTransactionQueryBuilderOf(c *gin.Context, userPayload *util.UserPayload) sq.SelectBuilder {
+	userID := userPayload.UserID
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	listTransactionQueryBuilder := psql.Select("transactions.id", "tenant.fullname AS tenant_fullname", "tenant.gender AS tenant_gender", "tenant.phone_number AS tenant_phone_number", "house.title AS house_title", "house.province_id AS house_province_id", "house.city_id AS house_city_id", "house.amenities AS house_amenities", "transactions.rental_unit AS house_type_rent", "payment_status", "payment_proof", "total_payment", "check_in", "check_out", "time_rent", "transactions.created_at", "transactions.updated_at").From("transactions").Join("users AS tenant ON tenant.id = transactions.tenant_id").Join("homes AS house ON house.id = transactions.house_id")
+
+	// a user holding both roles picks which side of the bookings to list by the as query,
+	// admins see every transaction
+	as := c.DefaultQuery("as", userPayload.UserRole)
+	switch {
+	case as == "owner" && userPayload.HasRole("owner"):
+		listTransactionQueryBuilder = listTransactionQueryBuilder.Where(sq.Eq{"transactions.owner_id": userID})
+	case as == "tenant" && userPayload.HasRole("tenant"):
+		listTransactionQueryBuilder = listTransactionQueryBuilder.Where(sq.Eq{"tenant_id": userID})
+	case userPayload.HasPermission(user.PermissionTransactionModerate):
+	default:
+		listTransactionQueryBuilder = listTransactionQueryBuilder.Where(sq.Eq{"tenant_id": userID})
+	}
+
+	statusFilter := c.Query("status")
+	if statusFilter != "" {
+		orQuery := sq.Or{}
+		for _, v := range strings.Split(statusFilter, ",") {
+			orQuery = append(orQuery, sq.Eq{"payment_status": v})
+		}
+		listTransactionQueryBuilder = listTransactionQueryBuilder.Where(orQuery)
+	}
+
+	return listTransactionQueryBuilder
+}
+
+// scanTransactionListRow scan a row of a query built by listTransactionQueryBuilderOf
+func scanTransactionListRow(rows *sql.Rows) (TransactionListRow, error) {
+	var i TransactionListRow
+	err := rows.Scan(
+		&i.ID,
+		&i.TenantFullname,
+		&i.TenantGender,
+		&i.TenantPhoneNumber,
+		&i.HouseTitle,
+		&i.HouseProvinceID,
+		&i.HouseCityID,
+		&i.HouseAmenities,
+		&i.HouseTypeRent,
+		&i.PaymentStatus,
+		&i.PaymentProof,
+		&i.TotalPayment,
+		&i.CheckIn,
+		&i.CheckOut,
+		&i.TimeRent,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	if err != nil {
+		return i, err
+	}
+
+	if i.PaymentProof != "" {
+		i.PaymentProof = paymentProofPath(i.ID)
+	}
+
+	return i, nil
+}
@@ -0,0 +1,61 @@
+package transaction
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gubuk-service/config"
+	db "gubuk-service/db"
+	sqlc "gubuk-service/db/sqlc"
+	"gubuk-service/event"
+)
+
+const (
+	expiryPollInterval = time.Minute
+	expiryBatchSize    = 50
+)
+
+// StartExpiryWorker cancel the bookings still waiting for their payment after config.BookingPaymentTTL
+// in the background, so their dates are freed & their tenants are told
+func StartExpiryWorker() {
+	go func() {
+		ticker := time.NewTicker(expiryPollInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			expireStale()
+		}
+	}()
+}
+
+func expireStale() {
+	for {
+		now := time.Now()
+		expiredTransactions, err := db.Queries.ExpireStaleTransaction(context.TODO(), sqlc.ExpireStaleTransactionParams{
+			UpdatedAt:     now,
+			CreatedBefore: now.Add(-config.BookingPaymentTTL),
+			BatchSize:     expiryBatchSize,
+		})
+		if err != nil {
+			log.Printf("couldn't expire stale transactions: %v", err)
+			return
+		}
+
+		for _, v := range expiredTransactions {
+			event.Publish(expiredEvent(v, "the payment is not made in time"))
+		}
+
+		if len(expiredTransactions) < expiryBatchSize {
+			return
+		}
+	}
+}
+
+// expiredEvent tell the tenant their booking is cancelled as it's payment never came
+func expiredEvent(transaction sqlc.Transaction, reason string) event.Event {
+	expired := transactionEvent(event.TransactionStatusChanged, transaction, "Your booking is expired, "+reason, transaction.TenantID)
+	expired.Data["reason"] = reason
+	expired.Data["cancel_reason"] = event.CancelReasonExpired
+	return expired
+}
@@ -0,0 +1,311 @@
+package transaction
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "gubuk-service/db"
+	"gubuk-service/mail"
+	"gubuk-service/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+// Formats the transactions could be exported as
+const (
+	exportFormatCSV  = "csv"
+	exportFormatXLSX = "xlsx"
+)
+
+// exportFlushEvery is how many csv rows are buffered before they're sent to the client
+const exportFlushEvery = 100
+
+// exportLocale is how the exported columns & dates are written in a language
+type exportLocale struct {
+	header     []string
+	dateLayout string
+	timeLayout string
+	// dateNumFmt & timeNumFmt are the xlsx equivalent of the layouts
+	dateNumFmt string
+	timeNumFmt string
+}
+
+var exportLocales = map[string]exportLocale{
+	"id": {
+		header:     []string{"ID", "Penyewa", "No. Telepon", "Rumah", "Satuan Sewa", "Status", "Check-in", "Check-out", "Lama Sewa", "Total Pembayaran", "Dibuat Pada"},
+		dateLayout: "02/01/2006",
+		timeLayout: "02/01/2006 15:04",
+		dateNumFmt: "dd/mm/yyyy",
+		timeNumFmt: "dd/mm/yyyy hh:mm",
+	},
+	"en": {
+		header:     []string{"ID", "Tenant", "Phone Number", "House", "Rental Unit", "Status", "Check-in", "Check-out", "Duration", "Total Payment", "Created At"},
+		dateLayout: "01/02/2006",
+		timeLayout: "01/02/2006 15:04",
+		dateNumFmt: "mm/dd/yyyy",
+		timeNumFmt: "mm/dd/yyyy hh:mm",
+	},
+}
+
+// transactionWriter write the exported transactions row by row, so they're never held in memory all at once,
+// Flush finish the export while Close release what it held even when the export is cut short
+type transactionWriter interface {
+	WriteRow(transaction TransactionListRow) error
+	Flush() error
+	Close() error
+}
+
+func ExportTransaction(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+
+	var req TransactionExportRequest
+	err := c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	if req.Format == "" {
+		req.Format = exportFormatCSV
+	}
+	if req.Lang == "" {
+		req.Lang = mail.Languages[0]
+	}
+
+	listTransactionQuery, args, err := listTransactionQueryBuilderOf(c, userPayload).OrderBy("transactions.created_at").ToSql()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	rows, err := db.DB.QueryContext(context.TODO(), listTransactionQuery, args...)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer rows.Close()
+
+	filename := fmt.Sprintf("transactions-%s.%s", time.Now().Format("20060102"), req.Format)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	var writer transactionWriter
+	locale := exportLocales[req.Lang]
+	switch req.Format {
+	case exportFormatXLSX:
+		c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		writer, err = newXLSXTransactionWriter(c.Writer, locale, req.Rupiah)
+	default:
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		writer, err = newCSVTransactionWriter(c.Writer, locale, req.Rupiah)
+	}
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer writer.Close()
+
+	// the response is already on it's way once the rows are written, so a failure
+	// could only be logged & the export is cut short
+	for rows.Next() {
+		i, err := scanTransactionListRow(rows)
+		if err != nil {
+			log.Printf("couldn't scan exported transaction: %v", err)
+			return
+		}
+
+		err = writer.WriteRow(i)
+		if err != nil {
+			log.Printf("couldn't write exported transaction %s: %v", i.ID, err)
+			return
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("couldn't export transactions: %v", err)
+		return
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		log.Printf("couldn't finish transactions export: %v", err)
+	}
+}
+
+type csvTransactionWriter struct {
+	w       *csv.Writer
+	flusher http.Flusher
+	locale  exportLocale
+	rupiah  bool
+	count   int
+}
+
+func newCSVTransactionWriter(w gin.ResponseWriter, locale exportLocale, rupiah bool) (*csvTransactionWriter, error) {
+	// the byte order mark makes spreadsheet apps read the file as utf-8
+	_, err := io.WriteString(w, "\ufeff")
+	if err != nil {
+		return nil, err
+	}
+
+	csvWriter := csv.NewWriter(w)
+	err = csvWriter.Write(locale.header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &csvTransactionWriter{
+		w:       csvWriter,
+		flusher: w,
+		locale:  locale,
+		rupiah:  rupiah,
+	}, nil
+}
+
+func (cw *csvTransactionWriter) WriteRow(transaction TransactionListRow) error {
+	totalPayment := strconv.FormatInt(transaction.TotalPayment, 10)
+	if cw.rupiah {
+		totalPayment = mail.FormatRupiah(transaction.TotalPayment)
+	}
+
+	err := cw.w.Write([]string{
+		transaction.ID.String(),
+		transaction.TenantFullname,
+		transaction.TenantPhoneNumber,
+		transaction.HouseTitle,
+		transaction.HouseTypeRent,
+		transaction.PaymentStatus,
+		transaction.CheckIn.Format(cw.locale.dateLayout),
+		transaction.CheckOut.Format(cw.locale.dateLayout),
+		transaction.TimeRent,
+		totalPayment,
+		transaction.CreatedAt.Format(cw.locale.timeLayout),
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.count++
+	if cw.count%exportFlushEvery == 0 {
+		cw.w.Flush()
+		cw.flusher.Flush()
+		return cw.w.Error()
+	}
+
+	return nil
+}
+
+func (cw *csvTransactionWriter) Flush() error {
+	cw.w.Flush()
+	cw.flusher.Flush()
+	return cw.w.Error()
+}
+
+func (cw *csvTransactionWriter) Close() error {
+	return nil
+}
+
+// xlsxTransactionWriter write the rows through a stream writer, which keeps them in a temporary file
+// instead of memory, the workbook is sent once it's complete
+type xlsxTransactionWriter struct {
+	w           io.Writer
+	file        *excelize.File
+	stream      *excelize.StreamWriter
+	row         int
+	dateStyle   int
+	timeStyle   int
+	amountStyle int
+}
+
+func newXLSXTransactionWriter(w io.Writer, locale exportLocale, rupiah bool) (xw *xlsxTransactionWriter, err error) {
+	file := excelize.NewFile()
+	defer func() {
+		if err != nil {
+			file.Close()
+		}
+	}()
+
+	dateStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: &locale.dateNumFmt})
+	if err != nil {
+		return nil, err
+	}
+
+	timeStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: &locale.timeNumFmt})
+	if err != nil {
+		return nil, err
+	}
+
+	amountNumFmt := "0"
+	if rupiah {
+		amountNumFmt = `"Rp"#,##0`
+	}
+	amountStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: &amountNumFmt})
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := file.NewStreamWriter(file.GetSheetName(0))
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]interface{}, 0, len(locale.header))
+	for _, v := range locale.header {
+		header = append(header, v)
+	}
+	err = stream.SetRow("A1", header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &xlsxTransactionWriter{
+		w:           w,
+		file:        file,
+		stream:      stream,
+		row:         1,
+		dateStyle:   dateStyle,
+		timeStyle:   timeStyle,
+		amountStyle: amountStyle,
+	}, nil
+}
+
+func (xw *xlsxTransactionWriter) WriteRow(transaction TransactionListRow) error {
+	xw.row++
+	cell, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return err
+	}
+
+	return xw.stream.SetRow(cell, []interface{}{
+		transaction.ID.String(),
+		transaction.TenantFullname,
+		transaction.TenantPhoneNumber,
+		transaction.HouseTitle,
+		transaction.HouseTypeRent,
+		transaction.PaymentStatus,
+		excelize.Cell{StyleID: xw.dateStyle, Value: transaction.CheckIn},
+		excelize.Cell{StyleID: xw.dateStyle, Value: transaction.CheckOut},
+		transaction.TimeRent,
+		excelize.Cell{StyleID: xw.amountStyle, Value: transaction.TotalPayment},
+		excelize.Cell{StyleID: xw.timeStyle, Value: transaction.CreatedAt},
+	})
+}
+
+func (xw *xlsxTransactionWriter) Flush() error {
+	err := xw.stream.Flush()
+	if err != nil {
+		return err
+	}
+
+	return xw.file.Write(xw.w)
+}
+
+// Close remove the temporary files of the stream writer
+func (xw *xlsxTransactionWriter) Close() error {
+	return xw.file.Close()
+}
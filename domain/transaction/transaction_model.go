@@ -3,13 +3,49 @@ package transaction
 import (
 	"time"
 
+	sqlc "gubuk-service/db/sqlc"
+
 	"github.com/google/uuid"
 )
 
+// chargeRefundDue is a charge paid after it's booking is already settled (e.g. cancelled or paid some other way),
+// the payment is kept on the charge to be refunded instead of changing the booking
+const chargeRefundDue = "refund_due"
+
 type TransactionCreateRequest struct {
 	HouseID  string    `form:"house_id" binding:"required"`
 	CheckIn  time.Time `form:"check_in" binding:"required"`
-	TimeRent int       `form:"time_rent" binding:"required"`
+	TimeRent int       `form:"time_rent" binding:"required,min=1"`
+
+	RentalPlanID string `form:"rental_plan_id"`
+}
+
+type TransactionExportRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv xlsx"`
+	Lang   string `form:"lang" binding:"omitempty,oneof=id en"`
+	Rupiah bool   `form:"rupiah"`
+}
+
+type TransactionDetail struct {
+	sqlc.Transaction
+	LineItems []sqlc.TransactionLineItem `json:"line_items"`
+}
+
+type PaymentSubmissionCreateRequest struct {
+	Amount        int64     `form:"amount" binding:"omitempty,min=1"`
+	TransferDate  time.Time `form:"transfer_date"`
+	PaymentMethod string    `form:"payment_method"`
+	Notes         string    `form:"notes"`
+}
+
+// TransactionStatusUpdateRequest is the status an owner could set a transaction to, a payment is rejected
+// by rejecting it's payment submission instead
+type TransactionStatusUpdateRequest struct {
+	Status string `form:"status" binding:"required,oneof=approved cancel"`
+}
+
+type PaymentSubmissionRejectRequest struct {
+	Reason string `form:"reason" binding:"required"`
 }
 
 type TransactionListRow struct {
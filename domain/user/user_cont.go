@@ -2,7 +2,9 @@ package user
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"log"
 	"time"
 
 	db "gubuk-service/db"
@@ -34,7 +36,15 @@ func Register(c *gin.Context) {
 		return
 	}
 
-	createdUserID, err := db.Queries.CreateUser(context.TODO(), sqlc.CreateUserParams{
+	tx, err := db.DB.BeginTx(context.TODO(), nil)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := db.Queries.WithTx(tx)
+
+	createdUserID, err := qtx.CreateUser(context.TODO(), sqlc.CreateUserParams{
 		ID:          uuid.New(),
 		Fullname:    req.Fullname,
 		Username:    req.Username,
@@ -51,6 +61,21 @@ func Register(c *gin.Context) {
 		return
 	}
 
+	err = qtx.AddUserRole(context.TODO(), sqlc.AddUserRoleParams{
+		UserID:   createdUserID,
+		RoleName: req.Role,
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
 	token, _, err := util.CreateToken(&util.UserPayload{
 		ID:        uuid.NewString(),
 		Username:  req.Username,
@@ -90,6 +115,11 @@ func Login(c *gin.Context) {
 		return
 	}
 
+	if user.SuspendedAt.Valid {
+		util.SendUnauthorized(c, errors.New("your account is suspended"))
+		return
+	}
+
 	token, _, err := util.CreateToken(&util.UserPayload{
 		ID:        uuid.NewString(),
 		Username:  user.Username,
@@ -112,7 +142,7 @@ func Logout(c *gin.Context) {
 	util.SendSuccess(c, nil)
 }
 
-// CheckAuth is validate a user session from it's token and return it's role & avatar
+// CheckAuth is validate a user session from it's token and return it's roles, permissions, avatar & number of unread messages & notifications
 func CheckAuth(c *gin.Context) {
 	payload, _ := c.Get("user")
 	userPayload, _ := payload.(*util.UserPayload)
@@ -131,10 +161,26 @@ func CheckAuth(c *gin.Context) {
 		return
 	}
 
+	unreadMessages, err := db.Queries.CountUnreadMessage(context.TODO(), id)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	unreadNotifications, err := db.Queries.CountUnreadNotification(context.TODO(), id)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
 	util.SendSuccess(c, gin.H{
-		"user_id":     userID,
-		"user_role":   userRole,
-		"user_avatar": userAvatar,
+		"user_id":              userID,
+		"user_role":            userRole,
+		"user_roles":           userPayload.UserRoles,
+		"permissions":          userPayload.Permissions,
+		"user_avatar":          userAvatar,
+		"unread_messages":      unreadMessages,
+		"unread_notifications": unreadNotifications,
 	})
 }
 
@@ -156,6 +202,7 @@ func GetUserDetail(c *gin.Context) {
 		return
 	}
 
+	util.SetETag(c, user.UpdatedAt)
 	util.SendSuccess(c, user)
 }
 
@@ -182,6 +229,17 @@ func UpdateUserAvatar(c *gin.Context) {
 		return
 	}
 
+	err = checkUserIfMatch(c, id)
+	if err != nil {
+		if errors.Is(err, util.ErrPreconditionFailed) {
+			util.SendPreconditionFailed(c, err)
+			return
+		}
+
+		util.SendServerError(c, err)
+		return
+	}
+
 	oldAvatarUrl, err := db.Queries.GetUserAvatarById(context.TODO(), id)
 	if err != nil {
 		util.SendBadRequest(c, err)
@@ -268,6 +326,48 @@ func UpdateUserPassword(c *gin.Context) {
 	util.SendSuccess(c, nil)
 }
 
+func UpdateUserPrivacy(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	var req UserPrivacyUpdateRequest
+	err = c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	err = checkUserIfMatch(c, id)
+	if err != nil {
+		if errors.Is(err, util.ErrPreconditionFailed) {
+			util.SendPreconditionFailed(c, err)
+			return
+		}
+
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = db.Queries.UpdateUserContactVisibilityById(context.TODO(), sqlc.UpdateUserContactVisibilityByIdParams{
+		ID:                id,
+		ContactVisibility: req.ContactVisibility,
+		UpdatedAt:         time.Now(),
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, req)
+}
+
 func UpdateUserProfile(c *gin.Context) {
 	payload, _ := c.Get("user")
 	userPayload, _ := payload.(*util.UserPayload)
@@ -279,26 +379,217 @@ func UpdateUserProfile(c *gin.Context) {
 		return
 	}
 
-	var req UserUpdateProfileRequest
+	user, err := db.Queries.GetUserById(context.TODO(), id)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = util.CheckIfMatch(c, user.UpdatedAt)
+	if err != nil {
+		util.SendPreconditionFailed(c, err)
+		return
+	}
+
+	req := UserUpdateProfileRequest{
+		Fullname:    user.Fullname,
+		Email:       user.Email,
+		Gender:      user.Gender,
+		PhoneNumber: user.PhoneNumber,
+		Address:     user.Address,
+	}
+	err = util.BindPatch(c, &req)
+	if err != nil {
+		util.SendValidationError(c, err)
+		return
+	}
+
+	// the profile is only updated when it's not changed since it's read above
+	updatedAt, err := db.Queries.UpdateUserById(context.TODO(), sqlc.UpdateUserByIdParams{
+		ID:                id,
+		Fullname:          req.Fullname,
+		Email:             req.Email,
+		Gender:            req.Gender,
+		PhoneNumber:       req.PhoneNumber,
+		Address:           req.Address,
+		UpdatedAt:         time.Now(),
+		ExpectedUpdatedAt: user.UpdatedAt,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			util.SendPreconditionFailed(c, util.ErrPreconditionFailed)
+			return
+		}
+
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SetETag(c, updatedAt)
+	util.SendSuccess(c, nil)
+}
+
+// UpgradeToOwner grant the owner role to a tenant, so the same account could list houses
+// while keeping it's bookings
+func UpgradeToOwner(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	if userPayload.HasRole("owner") {
+		util.SendBadRequest(c, errors.New("you are already an owner"))
+		return
+	}
+
+	err = db.Queries.AddUserRole(context.TODO(), sqlc.AddUserRoleParams{
+		UserID:   id,
+		RoleName: "owner",
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	roles, err := db.Queries.ListUserRole(context.TODO(), id)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, gin.H{
+		"user_roles": roles,
+	})
+}
+
+// DeleteUser anonymise the account of the currently logged in user, it's bookings & messages are kept
+// for the records of their counterparts while the rest of it's personal data is removed
+func DeleteUser(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	username := userPayload.Username
+	userID := userPayload.UserID
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	var req UserDeleteRequest
 	err = c.Bind(&req)
 	if err != nil {
 		util.SendBadRequest(c, err)
 		return
 	}
 
-	err = db.Queries.UpdateUserById(context.TODO(), sqlc.UpdateUserByIdParams{
-		ID:          id,
-		Fullname:    req.Fullname,
-		Email:       req.Email,
-		Gender:      req.Gender,
-		PhoneNumber: req.PhoneNumber,
-		Address:     req.Address,
-		UpdatedAt:   time.Now(),
+	user, err := db.Queries.GetUserByUsername(context.TODO(), username)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = util.CheckPassword(req.Password, user.Password)
+	if err != nil {
+		util.SendBadRequest(c, errors.New("wrong password"))
+		return
+	}
+
+	upcomingBookings, err := db.Queries.CountUpcomingUserTransaction(context.TODO(), sqlc.CountUpcomingUserTransactionParams{
+		TenantID: id,
+		CheckOut: time.Now(),
 	})
 	if err != nil {
 		util.SendServerError(c, err)
 		return
 	}
+	if upcomingBookings > 0 {
+		util.SendBadRequest(c, errors.New("you still have upcoming bookings, your account could be deleted once they're over"))
+		return
+	}
+
+	tx, err := db.DB.BeginTx(context.TODO(), nil)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := db.Queries.WithTx(tx)
+
+	deletedAt := sql.NullTime{Time: time.Now(), Valid: true}
+
+	// the password is emptied, so nobody could log in as the user anymore
+	err = qtx.AnonymizeUserById(context.TODO(), sqlc.AnonymizeUserByIdParams{
+		ID:        id,
+		Username:  "deleted-" + id.String(),
+		DeletedAt: deletedAt,
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	err = qtx.SoftDeleteHouseByOwner(context.TODO(), sqlc.SoftDeleteHouseByOwnerParams{
+		OwnerID:   id,
+		DeletedAt: deletedAt,
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	for _, deleteByUser := range []func(context.Context, uuid.UUID) error{
+		qtx.DeleteNotificationByUser,
+		qtx.DeleteNotificationPreference,
+		qtx.DeleteWebhookByUser,
+		qtx.DeleteFavoriteByUser,
+		qtx.DeleteSavedSearchByUser,
+		qtx.DeleteHouseImportByOwner,
+		qtx.DeleteIdempotencyKeyByUser,
+		qtx.DeleteUserRoleByUser,
+	} {
+		err = deleteByUser(context.TODO(), id)
+		if err != nil {
+			util.SendServerError(c, err)
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	// every session of the user is revoked along with this one, since VerifyAuth refuses a deleted user
+	c.SetCookie("token", "", 0, "", "", true, true)
+
+	if user.Avatar != "" {
+		err = media.DestroyMedia(user.Avatar)
+		if err != nil {
+			log.Printf("couldn't destroy the avatar of deleted user %s: %v", id, err)
+		}
+	}
 
 	util.SendSuccess(c, nil)
 }
+
+// checkUserIfMatch checks the If-Match header of a request against the current version of the user,
+// the user is only read when the header is sent
+func checkUserIfMatch(c *gin.Context, id uuid.UUID) error {
+	if c.GetHeader("If-Match") == "" {
+		return nil
+	}
+
+	user, err := db.Queries.GetUserById(context.TODO(), id)
+	if err != nil {
+		return err
+	}
+
+	return util.CheckIfMatch(c, user.UpdatedAt)
+}
@@ -0,0 +1,127 @@
+package user
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	db "gubuk-service/db"
+	sqlc "gubuk-service/db/sqlc"
+	"gubuk-service/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ExportUserData return the personal data of the currently logged in user, as a zip of json files
+// with ?format=zip or as a single json otherwise
+func ExportUserData(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	export, err := exportOf(id)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	if c.Query("format") != "zip" {
+		util.SendSuccess(c, export)
+		return
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="gubuk-export-%s.zip"`, export.Profile.Username))
+
+	archive := zip.NewWriter(c.Writer)
+	for name, data := range map[string]interface{}{
+		"profile.json":      gin.H{"profile": export.Profile, "roles": export.Roles, "notification_preference": export.NotificationPreference, "exported_at": export.ExportedAt},
+		"houses.json":       export.Houses,
+		"transactions.json": export.Transactions,
+		"messages.json":     export.Messages,
+	} {
+		file, err := archive.Create(name)
+		if err != nil {
+			log.Printf("couldn't write %s of the export of user %s: %v", name, id, err)
+			return
+		}
+
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(data)
+		if err != nil {
+			log.Printf("couldn't write %s of the export of user %s: %v", name, id, err)
+			return
+		}
+	}
+
+	err = archive.Close()
+	if err != nil {
+		log.Printf("couldn't finish the export of user %s: %v", id, err)
+	}
+}
+
+// exportOf gather the personal data of a user
+func exportOf(id uuid.UUID) (UserExport, error) {
+	export := UserExport{ExportedAt: time.Now()}
+
+	var err error
+	export.Profile, err = db.Queries.GetUserById(context.TODO(), id)
+	if err != nil {
+		return export, err
+	}
+
+	export.Roles, err = db.Queries.ListUserRole(context.TODO(), id)
+	if err != nil {
+		return export, err
+	}
+
+	preference, err := db.Queries.GetNotificationPreference(context.TODO(), id)
+	if err == nil {
+		export.NotificationPreference = &preference
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return export, err
+	}
+
+	export.Houses, err = db.Queries.ListHouseByOwner(context.TODO(), id)
+	if err != nil {
+		return export, err
+	}
+
+	export.Transactions, err = db.Queries.ListTransactionByUser(context.TODO(), id)
+	if err != nil {
+		return export, err
+	}
+
+	export.Messages, err = db.Queries.ListMessageByUser(context.TODO(), id)
+	if err != nil {
+		return export, err
+	}
+
+	if export.Roles == nil {
+		export.Roles = make([]string, 0)
+	}
+	if export.Houses == nil {
+		export.Houses = make([]sqlc.Home, 0)
+	}
+	if export.Transactions == nil {
+		export.Transactions = make([]sqlc.Transaction, 0)
+	}
+	if export.Messages == nil {
+		export.Messages = make([]sqlc.Message, 0)
+	}
+
+	return export, nil
+}
@@ -1,23 +1,59 @@
 package user
 
 import (
+	"context"
 	"errors"
 
+	db "gubuk-service/db"
 	"gubuk-service/util"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
-func VerifyAuth(c *gin.Context) {
+// Different types of error returned by the Authenticate function, along with the ones of util.VerifyToken
+var (
+	errUserNotExist = errors.New("user of the token is not exist")
+	errSuspended    = errors.New("your account is suspended")
+)
+
+// Authenticate verify the token of the request & load the current roles and permissions of it's user,
+// so a role change or a suspension takes effect right away, not when the token expires
+func Authenticate(c *gin.Context) (*util.UserPayload, error) {
 	token, err := c.Cookie("token")
 	if err != nil {
-		util.SendUnauthorized(c, err)
-		return
+		return nil, err
 	}
 
 	payload, err := util.VerifyToken(token)
 	if err != nil {
-		if errors.Is(err, util.ErrExpiredToken) {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(payload.UserID)
+	if err != nil {
+		return nil, util.ErrInvalidToken
+	}
+
+	access, err := db.Queries.GetUserAccessById(context.TODO(), userID)
+	if err != nil {
+		return nil, errUserNotExist
+	}
+	if access.SuspendedAt.Valid {
+		return nil, errSuspended
+	}
+
+	payload.UserRole = access.Role
+	payload.UserRoles = access.Roles
+	payload.Permissions = access.Permissions
+
+	return payload, nil
+}
+
+func VerifyAuth(c *gin.Context) {
+	payload, err := Authenticate(c)
+	if err != nil {
+		if errors.Is(err, util.ErrExpiredToken) || errors.Is(err, errUserNotExist) || errors.Is(err, errSuspended) {
 			c.SetCookie("token", "", 0, "", "", true, true)
 		}
 
@@ -29,14 +65,52 @@ func VerifyAuth(c *gin.Context) {
 	c.Next()
 }
 
+// OptionalAuth set the user of the request like VerifyAuth does when there's a valid token,
+// but still let an anonymous visitor through to a public endpoint
+func OptionalAuth(c *gin.Context) {
+	payload, err := Authenticate(c)
+	if err != nil {
+		if errors.Is(err, util.ErrExpiredToken) || errors.Is(err, errUserNotExist) || errors.Is(err, errSuspended) {
+			c.SetCookie("token", "", 0, "", "", true, true)
+		}
+
+		c.Next()
+		return
+	}
+
+	c.Set("user", payload)
+	c.Next()
+}
+
 func VerifyRole(role string) gin.HandlerFunc {
+	return VerifyAnyRole(role)
+}
+
+// VerifyAnyRole allow the user holding at least one of the roles
+func VerifyAnyRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload, _ := c.Get("user")
+		userPayload, _ := payload.(*util.UserPayload)
+
+		for _, role := range roles {
+			if userPayload.HasRole(role) {
+				c.Next()
+				return
+			}
+		}
+
+		util.SendUnauthorized(c, errors.New("your role could not access this api"))
+	}
+}
+
+// VerifyPermission allow the user holding a role which grants the permission
+func VerifyPermission(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		payload, _ := c.Get("user")
 		userPayload, _ := payload.(*util.UserPayload)
-		userRole := userPayload.UserRole
 
-		if userRole != role {
-			util.SendUnauthorized(c, errors.New("your role could not access this api"))
+		if !userPayload.HasPermission(permission) {
+			util.SendUnauthorized(c, errors.New("you don't have the permission to access this api"))
 			return
 		}
 
@@ -1,5 +1,32 @@
 package user
 
+import (
+	"time"
+
+	sqlc "gubuk-service/db/sqlc"
+)
+
+// Permissions granted by the roles, see the role_permissions table
+const (
+	PermissionHouseManage         = "house.manage"
+	PermissionTransactionCreate   = "transaction.create"
+	PermissionTransactionManage   = "transaction.manage"
+	PermissionReviewCreate        = "review.create"
+	PermissionReviewReply         = "review.reply"
+	PermissionWebhookManage       = "webhook.manage"
+	PermissionUserModerate        = "user.moderate"
+	PermissionHouseModerate       = "house.moderate"
+	PermissionTransactionModerate = "transaction.moderate"
+	PermissionAuditView           = "audit.view"
+	PermissionFavoriteManage      = "favorite.manage"
+)
+
+// Who could see an owner's contact details on the house detail
+const (
+	ContactVisibilityTenants  = "tenants"
+	ContactVisibilityBookings = "bookings"
+)
+
 type UserRegisterRequest struct {
 	Fullname    string `form:"fullname" binding:"required"`
 	Username    string `form:"username" binding:"required,min=3"`
@@ -21,10 +48,29 @@ type UserUpdatePasswordRequest struct {
 	NewPassword string `form:"new_password" binding:"required,min=8"`
 }
 
+// UserUpdateProfileRequest is filled with the current profile before it's bound, so only the sent fields change
 type UserUpdateProfileRequest struct {
-	Fullname    string `form:"fullname" binding:"required"`
-	Email       string `form:"email" binding:"required,email"`
-	Gender      string `form:"gender" binding:"required,oneof=male female"`
-	PhoneNumber string `form:"phone_number" binding:"required"`
-	Address     string `form:"address" binding:"required"`
+	Fullname    string `form:"fullname" json:"fullname" binding:"required"`
+	Email       string `form:"email" json:"email" binding:"required,email"`
+	Gender      string `form:"gender" json:"gender" binding:"required,oneof=male female"`
+	PhoneNumber string `form:"phone_number" json:"phone_number" binding:"required"`
+	Address     string `form:"address" json:"address" binding:"required"`
+}
+
+type UserPrivacyUpdateRequest struct {
+	ContactVisibility string `form:"contact_visibility" binding:"required,oneof=tenants bookings"`
+}
+
+type UserDeleteRequest struct {
+	Password string `form:"password" binding:"required"`
+}
+
+type UserExport struct {
+	Profile                sqlc.GetUserByIdRow          `json:"profile"`
+	Roles                  []string                     `json:"roles"`
+	NotificationPreference *sqlc.NotificationPreference `json:"notification_preference"`
+	Houses                 []sqlc.Home                  `json:"houses"`
+	Transactions           []sqlc.Transaction           `json:"transactions"`
+	Messages               []sqlc.Message               `json:"messages"`
+	ExportedAt             time.Time                    `json:"exported_at"`
 }
@@ -0,0 +1,237 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gubuk-service/config"
+	db "gubuk-service/db"
+	sqlc "gubuk-service/db/sqlc"
+	"gubuk-service/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const defaultDeliveryLimit = 20
+
+// CreateWebhook register a url to be posted the events it subscribe to, the secret to verify
+// the signature of the deliveries is only returned here
+func CreateWebhook(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+
+	userID, err := uuid.Parse(userPayload.UserID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	var req WebhookCreateRequest
+	err = c.Bind(&req)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	err = checkTarget(req.URL)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	secret := make([]byte, 32)
+	_, err = rand.Read(secret)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	newWebhook, err := db.Queries.CreateWebhook(context.TODO(), sqlc.CreateWebhookParams{
+		ID:       uuid.New(),
+		UserID:   userID,
+		Url:      req.URL,
+		Secret:   "whsec_" + hex.EncodeToString(secret),
+		Events:   req.Events,
+		IsGlobal: userPayload.HasRole("admin"),
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, newWebhook)
+}
+
+// ListWebhook return the webhooks of the currently logged in user
+func ListWebhook(c *gin.Context) {
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+
+	userID, err := uuid.Parse(userPayload.UserID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	webhooks, err := db.Queries.ListWebhookByUser(context.TODO(), userID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	webhookList := make([]WebhookRow, 0, len(webhooks))
+	for _, v := range webhooks {
+		webhookList = append(webhookList, WebhookRow{
+			ID:        v.ID,
+			URL:       v.Url,
+			Events:    v.Events,
+			IsGlobal:  v.IsGlobal,
+			CreatedAt: v.CreatedAt,
+		})
+	}
+
+	util.SendSuccess(c, webhookList)
+}
+
+// DeleteWebhook remove a webhook with it's deliveries
+func DeleteWebhook(c *gin.Context) {
+	webhook, err := getWebhook(c)
+	if err != nil {
+		util.SendNotFound(c, err)
+		return
+	}
+
+	err = db.Queries.DeleteWebhook(context.TODO(), webhook.ID)
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, nil)
+}
+
+// ListWebhookDelivery return a page of the delivery log of a webhook, latest first
+func ListWebhookDelivery(c *gin.Context) {
+	webhook, err := getWebhook(c)
+	if err != nil {
+		util.SendNotFound(c, err)
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit <= 0 || limit > defaultDeliveryLimit {
+		limit = defaultDeliveryLimit
+	}
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	deliveries, err := db.Queries.ListWebhookDelivery(context.TODO(), sqlc.ListWebhookDeliveryParams{
+		WebhookID: webhook.ID,
+		Limit:     int32(limit),
+		Offset:    int32(offset),
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	if deliveries == nil {
+		deliveries = make([]sqlc.WebhookDelivery, 0)
+	}
+
+	util.SendSuccess(c, deliveries)
+}
+
+// RedeliverWebhookDelivery queue a delivery to be sent again right away with a fresh set of attempts
+func RedeliverWebhookDelivery(c *gin.Context) {
+	webhook, err := getWebhook(c)
+	if err != nil {
+		util.SendNotFound(c, err)
+		return
+	}
+
+	errNotExist := errors.New("delivery with the provided id is not exist")
+	deliveryID, err := uuid.Parse(c.Param("delivery_id"))
+	if err != nil {
+		util.SendNotFound(c, errNotExist)
+		return
+	}
+
+	delivery, err := db.Queries.GetWebhookDeliveryById(context.TODO(), deliveryID)
+	if err != nil || delivery.WebhookID != webhook.ID {
+		util.SendNotFound(c, errNotExist)
+		return
+	}
+
+	if delivery.Status == deliveryPending {
+		util.SendBadRequest(c, errors.New("delivery is already waiting to be sent"))
+		return
+	}
+
+	err = db.Queries.RedeliverWebhookDelivery(context.TODO(), sqlc.RedeliverWebhookDeliveryParams{
+		ID:            delivery.ID,
+		NextAttemptAt: time.Now(),
+	})
+	if err != nil {
+		util.SendServerError(c, err)
+		return
+	}
+
+	util.SendSuccess(c, nil)
+}
+
+// EchoWebhook is a local stand-in of a webhook receiver, it log what it receive & respond
+// with the ?status= code (200 by default) so retries could be exercised offline
+func EchoWebhook(c *gin.Context) {
+	if !config.WebhookEchoEnabled {
+		util.SendNotFound(c, errors.New("webhook echo is not enabled"))
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		util.SendBadRequest(c, err)
+		return
+	}
+
+	log.Printf("webhook echo received %s delivery %s signed %s: %s",
+		c.GetHeader(EventHeader), c.GetHeader(DeliveryHeader), c.GetHeader(SignatureHeader), body)
+
+	status, err := strconv.Atoi(c.DefaultQuery("status", "200"))
+	if err != nil || status < 200 || status > 599 {
+		status = http.StatusOK
+	}
+
+	c.Status(status)
+}
+
+// getWebhook return the webhook referred by the id param when it's owned by the currently logged in user
+func getWebhook(c *gin.Context) (sqlc.Webhook, error) {
+	errNotExist := errors.New("webhook with the provided id is not exist")
+
+	payload, _ := c.Get("user")
+	userPayload, _ := payload.(*util.UserPayload)
+	userID := userPayload.UserID
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return sqlc.Webhook{}, errNotExist
+	}
+
+	webhook, err := db.Queries.GetWebhookById(context.TODO(), id)
+	if err != nil || webhook.UserID.String() != userID {
+		return sqlc.Webhook{}, errNotExist
+	}
+
+	return webhook, nil
+}
@@ -0,0 +1,228 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "gubuk-service/db"
+	sqlc "gubuk-service/db/sqlc"
+	"gubuk-service/event"
+
+	"github.com/google/uuid"
+)
+
+// Status of a webhook delivery
+const (
+	deliveryPending   = "pending"
+	deliveryDelivered = "delivered"
+	deliveryFailed    = "failed"
+)
+
+const (
+	deliveryPollInterval = 5 * time.Second
+	deliveryBatchSize    = 20
+	// deliveryLease is how long a claimed delivery is hidden from other workers, it must outlast
+	// sending a whole batch so a delivery is never sent twice at once
+	deliveryLease       = 5 * time.Minute
+	deliveryTimeout     = 10 * time.Second
+	maxDeliveryAttempts = 8
+	firstRetryDelay     = 30 * time.Second
+	maxLastErrorLength  = 500
+)
+
+// Headers sent along a delivery
+const (
+	EventHeader     = "X-Gubuk-Event"
+	DeliveryHeader  = "X-Gubuk-Delivery"
+	TimestampHeader = "X-Gubuk-Timestamp"
+	SignatureHeader = "X-Gubuk-Signature"
+)
+
+// payload is the body of a delivery, an event sent to several webhooks share the same event id
+type payload struct {
+	EventID    uuid.UUID              `json:"event_id"`
+	Type       string                 `json:"type"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Data       map[string]interface{} `json:"data"`
+}
+
+func init() {
+	event.Subscribe(enqueue)
+}
+
+// enqueue queue a delivery of an event to every webhook subscribing to it
+func enqueue(e event.Event) {
+	if !subscribable(e.Type) {
+		return
+	}
+
+	webhooks, err := db.Queries.ListWebhookByEvent(context.TODO(), sqlc.ListWebhookByEventParams{
+		EventType: e.Type,
+		OwnerID:   e.OwnerID,
+	})
+	if err != nil {
+		log.Printf("couldn't list webhooks of %s: %v", e.Type, err)
+		return
+	}
+
+	if len(webhooks) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(payload{
+		EventID:    uuid.New(),
+		Type:       e.Type,
+		OccurredAt: e.OccurredAt,
+		Data:       e.Data,
+	})
+	if err != nil {
+		log.Printf("couldn't encode %s webhook payload: %v", e.Type, err)
+		return
+	}
+
+	for _, v := range webhooks {
+		_, err = db.Queries.CreateWebhookDelivery(context.TODO(), sqlc.CreateWebhookDeliveryParams{
+			ID:        uuid.New(),
+			WebhookID: v.ID,
+			EventType: e.Type,
+			Payload:   body,
+		})
+		if err != nil {
+			log.Printf("couldn't queue %s delivery of webhook %s: %v", e.Type, v.ID, err)
+		}
+	}
+}
+
+// StartDeliveryWorker send the queued deliveries in the background, a failed delivery is retried
+// with an exponential backoff until it's delivered or run out of attempts
+func StartDeliveryWorker() {
+	go func() {
+		ticker := time.NewTicker(deliveryPollInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			deliverDue()
+		}
+	}()
+}
+
+func deliverDue() {
+	deliveries, err := db.Queries.ClaimDueWebhookDelivery(context.TODO(), sqlc.ClaimDueWebhookDeliveryParams{
+		LeaseUntil: time.Now().Add(deliveryLease),
+		BatchSize:  deliveryBatchSize,
+	})
+	if err != nil {
+		log.Printf("couldn't claim webhook deliveries: %v", err)
+		return
+	}
+
+	for _, v := range deliveries {
+		attempt(v)
+	}
+}
+
+// attempt send a delivery once & record it's result
+func attempt(delivery sqlc.WebhookDelivery) {
+	webhook, err := db.Queries.GetWebhookById(context.TODO(), delivery.WebhookID)
+	if err != nil {
+		log.Printf("couldn't get webhook of delivery %s: %v", delivery.ID, err)
+		return
+	}
+
+	statusCode, err := send(webhook, delivery)
+
+	now := time.Now()
+	params := sqlc.UpdateWebhookDeliveryAttemptParams{
+		ID:             delivery.ID,
+		Attempts:       delivery.Attempts + 1,
+		NextAttemptAt:  now,
+		LastStatusCode: int32(statusCode),
+		UpdatedAt:      now,
+	}
+	switch {
+	case err == nil:
+		params.Status = deliveryDelivered
+		params.DeliveredAt = sql.NullTime{Time: now, Valid: true}
+	case params.Attempts >= maxDeliveryAttempts:
+		params.Status = deliveryFailed
+		params.LastError = truncate(err.Error(), maxLastErrorLength)
+	default:
+		params.Status = deliveryPending
+		params.NextAttemptAt = now.Add(retryDelay(params.Attempts))
+		params.LastError = truncate(err.Error(), maxLastErrorLength)
+	}
+
+	err = db.Queries.UpdateWebhookDeliveryAttempt(context.TODO(), params)
+	if err != nil {
+		log.Printf("couldn't record attempt of delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// send post a signed delivery to it's webhook, any non 2xx response is a failure
+func send(webhook sqlc.Webhook, delivery sqlc.WebhookDelivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, webhook.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gubuk-webhook")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, timestamp, delivery.Payload))
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+
+	return res.StatusCode, nil
+}
+
+// Sign return the signature of a delivery body sent at the timestamp, a receiver verify it
+// by computing the HMAC-SHA256 of "<timestamp>.<body>" with the secret of it's webhook
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryDelay double the wait after every failed attempt: 30s, 1m, 2m, 4m, ...
+func retryDelay(attempts int32) time.Duration {
+	return firstRetryDelay << (attempts - 1)
+}
+
+func subscribable(eventType string) bool {
+	switch eventType {
+	case event.TransactionCreated, event.PaymentProofUploaded, event.TransactionStatusChanged, event.HouseUpdated:
+		return true
+	}
+	return false
+}
+
+func truncate(s string, length int) string {
+	if len(s) <= length {
+		return s
+	}
+	return s[:length]
+}
@@ -0,0 +1,20 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookCreateRequest struct {
+	URL    string   `form:"url" json:"url" binding:"required,url,max=2000"`
+	Events []string `form:"events" json:"events" binding:"required,min=1,dive,oneof=transaction.created transaction.payment_proof_uploaded transaction.status_changed house.updated"`
+}
+
+type WebhookRow struct {
+	ID        uuid.UUID `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	IsGlobal  bool      `json:"is_global"`
+	CreatedAt time.Time `json:"created_at"`
+}
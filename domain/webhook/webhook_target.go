@@ -0,0 +1,87 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"gubuk-service/config"
+)
+
+// ErrForbiddenTarget is returned for a webhook url pointing to the service's own network, which
+// would let a user read the internal services through the delivery log
+var ErrForbiddenTarget = errors.New("url should not point to a loopback, private or link-local address")
+
+// sharedAddressSpace is the carrier-grade NAT range, it's as internal as a private one but isn't
+// covered by net.IP.IsPrivate
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// client deliver the webhooks without a proxy, as the address it dials is checked instead of the proxy's,
+// the check is done on the resolved address so a host resolving to an internal one later is still refused
+var client = &http.Client{
+	Timeout: deliveryTimeout,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: deliveryTimeout,
+			Control: controlTarget,
+		}).DialContext,
+		TLSHandshakeTimeout: deliveryTimeout,
+		IdleConnTimeout:     90 * time.Second,
+	},
+}
+
+// checkTarget validate the url of a webhook when it's registered, every address it's host resolves to
+// should be a public one
+func checkTarget(rawURL string) error {
+	targetURL, err := url.Parse(rawURL)
+	if err != nil || (targetURL.Scheme != "http" && targetURL.Scheme != "https") || targetURL.Hostname() == "" {
+		return errors.New("url should be an http or https url")
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(context.TODO(), targetURL.Hostname())
+	if err != nil {
+		return fmt.Errorf("couldn't resolve the host of the url: %w", err)
+	}
+
+	for _, v := range addrs {
+		if isForbiddenIP(v.IP) {
+			return ErrForbiddenTarget
+		}
+	}
+
+	return nil
+}
+
+// controlTarget refuse to connect to an internal address, it's run on the resolved address of every
+// connection a delivery makes, including the ones of a redirect
+func controlTarget(network string, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || isForbiddenIP(ip) {
+		return ErrForbiddenTarget
+	}
+
+	return nil
+}
+
+// isForbiddenIP tell whether an address is internal, a loopback one is allowed while the echo receiver is
+// enabled, as it's the service itself standing in for a webhook receiver during development
+func isForbiddenIP(ip net.IP) bool {
+	return (ip.IsLoopback() && !config.WebhookEchoEnabled) ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip)
+}
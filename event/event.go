@@ -0,0 +1,78 @@
+// Package event is an in-process event bus, handlers update other parts of the service
+// (notifications, webhooks, emails, ...) about what happened without the publisher knowing them
+package event
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	TransactionCreated       = "transaction.created"
+	PaymentProofUploaded     = "transaction.payment_proof_uploaded"
+	TransactionStatusChanged = "transaction.status_changed"
+	MessageSent              = "message.sent"
+	HouseUpdated             = "house.updated"
+	HousePublished           = "house.published"
+	SavedSearchMatched       = "saved_search.matched"
+)
+
+// Why a booking is cancelled, as the "cancel_reason" data of a TransactionStatusChanged event
+const (
+	CancelReasonExpired = "expired"
+	CancelReasonOwner   = "owner"
+	CancelReasonAdmin   = "admin"
+)
+
+type Event struct {
+	Type string
+	// Recipients are the users the event is about to be told to
+	Recipients []uuid.UUID
+	// OwnerID is the owner of the house or the transaction the event is about
+	OwnerID uuid.UUID
+	// Message is a human readable summary of the event
+	Message    string
+	Data       map[string]interface{}
+	OccurredAt time.Time
+}
+
+type Handler func(Event)
+
+var (
+	mu       sync.RWMutex
+	handlers []Handler
+)
+
+// Subscribe register a handler which is called on every published event
+func Subscribe(handler Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	handlers = append(handlers, handler)
+}
+
+// Publish pass an event to every handler asynchronously, so a slow or failing handler
+// never hold up nor break the request which publish it
+func Publish(e Event) {
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now()
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, handler := range handlers {
+		go func(handler Handler) {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("event handler of %s panic: %v", e.Type, r)
+				}
+			}()
+
+			handler(e)
+		}(handler)
+	}
+}
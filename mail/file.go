@@ -0,0 +1,37 @@
+package mail
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fileMailer write every message as an .eml file to a directory instead of sending it,
+// so emails could be inspected during local development
+type fileMailer struct {
+	dir  string
+	from string
+}
+
+func newFileMailer(dir string, from string) *fileMailer {
+	return &fileMailer{
+		dir:  dir,
+		from: from,
+	}
+}
+
+func (m *fileMailer) Send(message Message) error {
+	err := os.MkdirAll(m.dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	recipient := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(message.To)
+	name := fmt.Sprintf("%s-%s-%s.eml", time.Now().Format("20060102T150405"), recipient, uuid.NewString()[:8])
+
+	return os.WriteFile(filepath.Join(m.dir, name), compose(m.from, message), 0644)
+}
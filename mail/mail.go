@@ -0,0 +1,78 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gubuk-service/config"
+	"log"
+	"mime"
+	"time"
+)
+
+// ErrDisabled is returned when no mailer is configured
+var ErrDisabled = errors.New("mailer is not enabled")
+
+// Message is an html email to a single recipient
+type Message struct {
+	To      string
+	Subject string
+	HTML    string
+}
+
+// Mailer deliver an email message
+type Mailer interface {
+	Send(message Message) error
+}
+
+var mailer Mailer
+
+func init() {
+	from := config.MailFrom
+	if from == "" {
+		from = "Gubuk <no-reply@gubuk.id>"
+	}
+
+	switch config.MailDriver {
+	case "":
+		mailer = nil
+	case "smtp":
+		mailer = newSMTPMailer(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword, from)
+	case "file":
+		dir := config.MailDir
+		if dir == "" {
+			dir = "mail-outbox"
+		}
+		mailer = newFileMailer(dir, from)
+	default:
+		log.Fatal("unknown mail driver: ", config.MailDriver)
+	}
+}
+
+// Enabled return whether a mailer is configured
+func Enabled() bool {
+	return mailer != nil
+}
+
+// Send deliver a message through the configured mailer
+func Send(message Message) error {
+	if mailer == nil {
+		return ErrDisabled
+	}
+
+	return mailer.Send(message)
+}
+
+// compose build a MIME message of an html email ready to be sent or stored
+func compose(from string, message Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", message.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(message.HTML)
+	return b.Bytes()
+}
@@ -0,0 +1,39 @@
+package mail
+
+import (
+	"net"
+	"net/mail"
+	"net/smtp"
+)
+
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func newSMTPMailer(host string, port string, username string, password string, from string) *smtpMailer {
+	if port == "" {
+		port = "587"
+	}
+
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &smtpMailer{
+		addr: net.JoinHostPort(host, port),
+		auth: auth,
+		from: from,
+	}
+}
+
+func (m *smtpMailer) Send(message Message) error {
+	sender, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(m.addr, m.auth, sender.Address, []string{message.To}, compose(m.from, message))
+}
@@ -0,0 +1,97 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+)
+
+// Languages an email could be written in, the first one is the default
+var Languages = []string{"id", "en"}
+
+// Names of the email templates
+const (
+	TemplateNewBooking           = "new_booking"
+	TemplatePaymentProofUploaded = "payment_proof_uploaded"
+	TemplateBookingApproved      = "booking_approved"
+	TemplateBookingRejected      = "booking_rejected"
+	TemplateBookingExpired       = "booking_expired"
+	TemplateBookingCancelled     = "booking_cancelled"
+	TemplateSavedSearchMatch     = "saved_search_match"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var templates = make(map[string]*template.Template)
+
+func init() {
+	names := []string{
+		TemplateNewBooking,
+		TemplatePaymentProofUploaded,
+		TemplateBookingApproved,
+		TemplateBookingRejected,
+		TemplateBookingExpired,
+		TemplateBookingCancelled,
+		TemplateSavedSearchMatch,
+	}
+
+	for _, lang := range Languages {
+		for _, name := range names {
+			templates[lang+"/"+name] = template.Must(template.ParseFS(templateFS, "templates/layout.html", "templates/"+lang+"/"+name+".html"))
+		}
+	}
+}
+
+// Render build a message from a template in a language, falling back to the default language
+func Render(to string, lang string, name string, data interface{}) (Message, error) {
+	t, ok := templates[lang+"/"+name]
+	if !ok {
+		t, ok = templates[Languages[0]+"/"+name]
+	}
+	if !ok {
+		return Message{}, fmt.Errorf("email template %s is not exist", name)
+	}
+
+	var subject bytes.Buffer
+	err := t.ExecuteTemplate(&subject, "subject", data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	var body bytes.Buffer
+	err = t.ExecuteTemplate(&body, "layout", data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		To: to,
+		// the subject is a header, not html, so it's unescaped back to plain text
+		Subject: html.UnescapeString(strings.TrimSpace(subject.String())),
+		HTML:    body.String(),
+	}, nil
+}
+
+// FormatRupiah format an amount as rupiah with dot thousand separators, e.g. Rp1.500.000
+func FormatRupiah(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := fmt.Sprint(amount)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+
+	return sign + "Rp" + b.String()
+}
@@ -2,6 +2,11 @@ package main
 
 import (
 	"gubuk-service/config"
+	"gubuk-service/domain/house"
+	"gubuk-service/domain/idempotency"
+	"gubuk-service/domain/transaction"
+	"gubuk-service/domain/webhook"
+	"gubuk-service/util"
 	"log"
 	"net/http"
 	"strings"
@@ -13,10 +18,12 @@ import (
 
 func main() {
 	router := gin.Default()
+	util.RegisterFieldNames()
 
 	router.Use(cors.New(cors.Config{
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Authorization", "Content-type"},
+		AllowHeaders:     []string{"Origin", "Authorization", "Content-type", "Idempotency-Key", "If-Match"},
+		ExposeHeaders:    []string{"Idempotent-Replayed", "ETag"},
 		AllowAllOrigins:  true,
 		AllowCredentials: true,
 	}))
@@ -34,6 +41,11 @@ func main() {
 	})
 
 	SetRoutes(router)
+	webhook.StartDeliveryWorker()
+	house.StartSavedSearchDigestWorker()
+	house.StartHouseImportWorker()
+	idempotency.StartCleanupWorker()
+	transaction.StartExpiryWorker()
 
 	if config.Port != "" {
 		log.Fatal(router.Run("0.0.0.0:" + config.Port))
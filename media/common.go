@@ -22,14 +22,19 @@ func extractPublicId(url string) string {
 }
 
 func ValidateImage(image *multipart.FileHeader) error {
+	return ValidateImageFile(image.Filename, image.Size)
+}
+
+// ValidateImageFile validate an image which isn't uploaded through a form, e.g. an image inside an archive
+func ValidateImageFile(filename string, size int64) error {
 	// validate image extension
 	var imageType, _ = regexp.Compile(`^.*\.(jpeg|JPEG|jpg|JPG|gif|GIF|png|PNG|svg|SVG|webp|WebP|WEBP)$`)
-	if isImage := imageType.MatchString(image.Filename); !isImage {
+	if isImage := imageType.MatchString(filename); !isImage {
 		return errors.New("invalid file type")
 	}
 
 	// validate image size, max 1 MB (1048576 bytes)
-	if image.Size > 1048576 {
+	if size > 1048576 {
 		return errors.New("invalid file size")
 	}
 
@@ -2,14 +2,23 @@ package media
 
 import (
 	"context"
+	"errors"
 	"gubuk-service/config"
+	"io"
 	"log"
 	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/cloudinary/cloudinary-go"
+	"github.com/cloudinary/cloudinary-go/api"
 	"github.com/cloudinary/cloudinary-go/api/uploader"
 )
 
+// PrivateMediaURLTTL is how long a signed url of a private media stays valid
+const PrivateMediaURLTTL = 5 * time.Minute
+
 var cld *cloudinary.Cloudinary
 
 func init() {
@@ -37,6 +46,85 @@ func UploadMedia(folder string, media *multipart.FileHeader) (string, error) {
 	return uploadResult.SecureURL, nil
 }
 
+// UploadMediaReader upload media which isn't uploaded through a form, e.g. an image inside an archive
+func UploadMediaReader(folder string, media io.Reader) (string, error) {
+	uploadResult, err := cld.Upload.Upload(context.TODO(), media, uploader.UploadParams{
+		Folder: folder,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return secureURLOf(uploadResult)
+}
+
+// UploadRemoteMedia upload media from a public url, it's fetched by cloudinary instead of the service
+func UploadRemoteMedia(folder string, mediaUrl string) (string, error) {
+	uploadResult, err := cld.Upload.Upload(context.TODO(), mediaUrl, uploader.UploadParams{
+		Folder: folder,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return secureURLOf(uploadResult)
+}
+
+// secureURLOf return the url of an uploaded media, or the error cloudinary rejected it with
+// (e.g. a file which isn't an image or a url which couldn't be fetched)
+func secureURLOf(uploadResult *uploader.UploadResult) (string, error) {
+	if uploadResult.Error.Message != "" {
+		return "", errors.New(uploadResult.Error.Message)
+	}
+
+	return uploadResult.SecureURL, nil
+}
+
+// UploadPrivateMedia upload media as an authenticated asset, the returned url
+// couldn't be accessed directly, use SignPrivateMedia to get an accessible url
+func UploadPrivateMedia(folder string, media *multipart.FileHeader) (string, error) {
+	file, err := media.Open()
+	if err != nil {
+		return "", err
+	}
+
+	uploadResult, err := cld.Upload.Upload(context.TODO(), file, uploader.UploadParams{
+		Folder: folder,
+		Type:   api.Authenticated,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return secureURLOf(uploadResult)
+}
+
+// UploadRemotePrivateMedia upload a media from a public url as an authenticated asset, e.g. to make
+// a media uploaded publicly before private, the public one is left to be destroyed by the caller
+func UploadRemotePrivateMedia(folder string, mediaUrl string) (string, error) {
+	uploadResult, err := cld.Upload.Upload(context.TODO(), mediaUrl, uploader.UploadParams{
+		Folder: folder,
+		Type:   api.Authenticated,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return secureURLOf(uploadResult)
+}
+
+// SignPrivateMedia return a short-lived url of a media uploaded by UploadPrivateMedia
+func SignPrivateMedia(privateMediaUrl string) (string, error) {
+	expiresAt := time.Now().Add(PrivateMediaURLTTL)
+
+	return cld.Upload.PrivateDownloadUrl(uploader.PrivateDownloadUrlParams{
+		PublicID:     extractPublicId(privateMediaUrl),
+		Format:       strings.TrimPrefix(filepath.Ext(privateMediaUrl), "."),
+		DeliveryType: api.Authenticated,
+		ExpiresAt:    &expiresAt,
+	})
+}
+
 func DestroyMedia(destroyedMediaUrl string) error {
 	publicId := extractPublicId(destroyedMediaUrl)
 
@@ -50,6 +138,21 @@ func DestroyMedia(destroyedMediaUrl string) error {
 	return nil
 }
 
+// DestroyPrivateMedia destroy a media uploaded by UploadPrivateMedia
+func DestroyPrivateMedia(destroyedMediaUrl string) error {
+	publicId := extractPublicId(destroyedMediaUrl)
+
+	_, err := cld.Upload.Destroy(context.TODO(), uploader.DestroyParams{
+		PublicID: publicId,
+		Type:     api.Authenticated,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func UpdateMedia(folder string, destroyedMediaUrl string, media *multipart.FileHeader) (string, error) {
 	newMediaUrl, err := UploadMedia(folder, media)
 	if err != nil {
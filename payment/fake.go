@@ -0,0 +1,139 @@
+package payment
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"gubuk-service/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+const fakeSignatureHeader = "X-Fake-Signature"
+
+// fakeProvider is an offline payment provider, it's charges are paid through the Simulate handler
+type fakeProvider struct {
+	secret  string
+	mu      sync.Mutex
+	charges map[string]int64
+}
+
+func newFakeProvider(secret string) *fakeProvider {
+	return &fakeProvider{
+		secret:  secret,
+		charges: make(map[string]int64),
+	}
+}
+
+type fakeNotification struct {
+	ChargeID    string `json:"charge_id"`
+	ProviderRef string `json:"provider_ref"`
+	Status      string `json:"status"`
+	Amount      int64  `json:"amount"`
+}
+
+func (p *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *fakeProvider) CreateCharge(charge Charge) (ChargeResult, error) {
+	p.mu.Lock()
+	p.charges[charge.ID] = charge.Amount
+	p.mu.Unlock()
+
+	return ChargeResult{
+		ProviderRef: "fake-" + charge.ID,
+		RedirectURL: "/api/payments/simulator/" + charge.ID,
+	}, nil
+}
+
+func (p *fakeProvider) ParseNotification(r *http.Request) (Notification, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return Notification{}, err
+	}
+
+	signature, err := hex.DecodeString(r.Header.Get(fakeSignatureHeader))
+	if err != nil || !hmac.Equal(signature, p.sign(body)) {
+		return Notification{}, ErrInvalidSignature
+	}
+
+	var notification fakeNotification
+	err = json.Unmarshal(body, &notification)
+	if err != nil {
+		return Notification{}, err
+	}
+
+	return Notification(notification), nil
+}
+
+func (p *fakeProvider) sign(body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(p.secret))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// Simulate act as the fake provider paying (or failing with ?status=failed) a charge, the signed
+// notification is passed to the webhook handler in-process, so the request never leaves the service
+func Simulate(webhook gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fake, ok := provider.(*fakeProvider)
+		if !ok {
+			util.SendNotFound(c, errors.New("payment simulator is only available for the fake provider"))
+			return
+		}
+
+		chargeID := c.Param("id")
+		fake.mu.Lock()
+		amount, ok := fake.charges[chargeID]
+		fake.mu.Unlock()
+		if !ok {
+			util.SendNotFound(c, errors.New("charge with the provided id is not exist"))
+			return
+		}
+
+		status := c.DefaultQuery("status", StatusPaid)
+		if status != StatusPaid && status != StatusFailed && status != StatusPending && status != StatusExpired {
+			util.SendBadRequest(c, errors.New("status should be one of paid, failed, pending or expired"))
+			return
+		}
+
+		body, err := json.Marshal(fakeNotification{
+			ChargeID:    chargeID,
+			ProviderRef: "fake-" + chargeID,
+			Status:      status,
+			Amount:      amount,
+		})
+		if err != nil {
+			util.SendServerError(c, err)
+			return
+		}
+
+		req, err := http.NewRequestWithContext(c.Request.Context(), http.MethodPost, "/api/payments/webhook", bytes.NewReader(body))
+		if err != nil {
+			util.SendServerError(c, err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(fakeSignatureHeader, hex.EncodeToString(fake.sign(body)))
+
+		recorder := httptest.NewRecorder()
+		webhookContext, _ := gin.CreateTestContext(recorder)
+		webhookContext.Request = req
+		webhook(webhookContext)
+
+		util.SendSuccess(c, gin.H{
+			"charge_id":      chargeID,
+			"status":         status,
+			"webhook_status": recorder.Code,
+		})
+	}
+}
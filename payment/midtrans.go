@@ -0,0 +1,191 @@
+package payment
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	midtransSandboxURL    = "https://app.sandbox.midtrans.com/snap/v1/transactions"
+	midtransProductionURL = "https://app.midtrans.com/snap/v1/transactions"
+)
+
+// midtransProvider charge a transaction through Midtrans Snap
+type midtransProvider struct {
+	serverKey string
+	snapURL   string
+	client    *http.Client
+}
+
+func newMidtransProvider(serverKey string, isProduction bool) *midtransProvider {
+	snapURL := midtransSandboxURL
+	if isProduction {
+		snapURL = midtransProductionURL
+	}
+
+	return &midtransProvider{
+		serverKey: serverKey,
+		snapURL:   snapURL,
+		client:    &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type midtransChargeRequest struct {
+	TransactionDetails struct {
+		OrderID     string `json:"order_id"`
+		GrossAmount int64  `json:"gross_amount"`
+	} `json:"transaction_details"`
+	CustomerDetails struct {
+		FirstName string `json:"first_name"`
+		Email     string `json:"email"`
+		Phone     string `json:"phone"`
+	} `json:"customer_details"`
+	ItemDetails []midtransItem `json:"item_details"`
+}
+
+type midtransItem struct {
+	ID       string `json:"id"`
+	Price    int64  `json:"price"`
+	Quantity int    `json:"quantity"`
+	Name     string `json:"name"`
+}
+
+type midtransChargeResponse struct {
+	Token         string   `json:"token"`
+	RedirectURL   string   `json:"redirect_url"`
+	ErrorMessages []string `json:"error_messages"`
+}
+
+type midtransNotification struct {
+	OrderID           string `json:"order_id"`
+	TransactionID     string `json:"transaction_id"`
+	TransactionStatus string `json:"transaction_status"`
+	FraudStatus       string `json:"fraud_status"`
+	StatusCode        string `json:"status_code"`
+	GrossAmount       string `json:"gross_amount"`
+	SignatureKey      string `json:"signature_key"`
+}
+
+func (p *midtransProvider) Name() string {
+	return "midtrans"
+}
+
+func (p *midtransProvider) CreateCharge(charge Charge) (ChargeResult, error) {
+	var reqBody midtransChargeRequest
+	reqBody.TransactionDetails.OrderID = charge.ID
+	reqBody.TransactionDetails.GrossAmount = charge.Amount
+	reqBody.CustomerDetails.FirstName = charge.CustomerName
+	reqBody.CustomerDetails.Email = charge.CustomerEmail
+	reqBody.CustomerDetails.Phone = charge.CustomerPhone
+	reqBody.ItemDetails = []midtransItem{{
+		ID:       charge.ID,
+		Price:    charge.Amount,
+		Quantity: 1,
+		Name:     truncate(charge.Description, 50),
+	}}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return ChargeResult{}, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.snapURL, bytes.NewReader(body))
+	if err != nil {
+		return ChargeResult{}, err
+	}
+	req.SetBasicAuth(p.serverKey, "")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return ChargeResult{}, err
+	}
+	defer res.Body.Close()
+
+	var resBody midtransChargeResponse
+	err = json.NewDecoder(res.Body).Decode(&resBody)
+	if err != nil {
+		return ChargeResult{}, err
+	}
+
+	if res.StatusCode != http.StatusCreated {
+		return ChargeResult{}, fmt.Errorf("midtrans: failed to create charge: %s", strings.Join(resBody.ErrorMessages, ", "))
+	}
+
+	return ChargeResult{
+		ProviderRef: resBody.Token,
+		RedirectURL: resBody.RedirectURL,
+	}, nil
+}
+
+func (p *midtransProvider) ParseNotification(r *http.Request) (Notification, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return Notification{}, err
+	}
+
+	var notification midtransNotification
+	err = json.Unmarshal(body, &notification)
+	if err != nil {
+		return Notification{}, err
+	}
+
+	// signature_key = sha512(order_id + status_code + gross_amount + server_key)
+	hash := sha512.Sum512([]byte(notification.OrderID + notification.StatusCode + notification.GrossAmount + p.serverKey))
+	expectedSignature := hex.EncodeToString(hash[:])
+	if subtle.ConstantTimeCompare([]byte(expectedSignature), []byte(notification.SignatureKey)) != 1 {
+		return Notification{}, ErrInvalidSignature
+	}
+
+	// gross_amount is sent as a decimal string, e.g. "150000.00"
+	grossAmount, err := strconv.ParseFloat(notification.GrossAmount, 64)
+	if err != nil {
+		return Notification{}, errors.New("midtrans: invalid gross amount")
+	}
+
+	return Notification{
+		ChargeID:    notification.OrderID,
+		ProviderRef: notification.TransactionID,
+		Status:      midtransStatus(notification.TransactionStatus, notification.FraudStatus),
+		Amount:      int64(grossAmount),
+	}, nil
+}
+
+// midtransStatus map a midtrans transaction status to a charge status
+func midtransStatus(transactionStatus string, fraudStatus string) string {
+	switch transactionStatus {
+	case "settlement":
+		return StatusPaid
+	case "capture":
+		if fraudStatus == "accept" || fraudStatus == "" {
+			return StatusPaid
+		}
+		return StatusPending
+	case "expire":
+		return StatusExpired
+	case "deny", "cancel", "failure":
+		return StatusFailed
+	default:
+		return StatusPending
+	}
+}
+
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+
+	return string(runes[:length])
+}
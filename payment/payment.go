@@ -0,0 +1,104 @@
+package payment
+
+import (
+	"errors"
+	"gubuk-service/config"
+	"log"
+	"net/http"
+)
+
+// Status of a charge reported by a payment provider
+const (
+	StatusPending = "pending"
+	StatusPaid    = "paid"
+	StatusFailed  = "failed"
+	// StatusExpired is a charge which isn't paid within the time the provider allows
+	StatusExpired = "expired"
+)
+
+// Different types of error returned by the payment provider
+var (
+	ErrDisabled         = errors.New("payment gateway is not enabled")
+	ErrInvalidSignature = errors.New("notification signature is invalid")
+)
+
+// Charge is a bill of a transaction sent to the payment provider
+type Charge struct {
+	ID            string
+	Amount        int64
+	Description   string
+	CustomerName  string
+	CustomerEmail string
+	CustomerPhone string
+}
+
+// ChargeResult is the created charge on the payment provider side
+type ChargeResult struct {
+	ProviderRef string
+	RedirectURL string
+}
+
+// Notification is a verified charge status update sent by the payment provider
+type Notification struct {
+	ChargeID    string
+	ProviderRef string
+	Status      string
+	Amount      int64
+}
+
+// Provider is a payment gateway which could charge a transaction & notify it's result through a webhook
+type Provider interface {
+	Name() string
+	CreateCharge(charge Charge) (ChargeResult, error)
+	ParseNotification(r *http.Request) (Notification, error)
+}
+
+var provider Provider
+
+func init() {
+	switch config.PaymentProvider {
+	case "":
+		provider = nil
+	case "midtrans":
+		provider = newMidtransProvider(config.MidtransServerKey, config.MidtransIsProduction)
+	case "fake":
+		// the notifications are signed with a secret of their own, the JWT key is never reused for them
+		if config.PaymentWebhookSecret == "" {
+			log.Fatal("PAYMENT_WEBHOOK_SECRET is required for the fake payment provider")
+		}
+		provider = newFakeProvider(config.PaymentWebhookSecret)
+	default:
+		log.Fatal("unknown payment provider: ", config.PaymentProvider)
+	}
+}
+
+// Enabled return whether a payment provider is configured
+func Enabled() bool {
+	return provider != nil
+}
+
+// ProviderName return the name of the configured payment provider
+func ProviderName() string {
+	if provider == nil {
+		return ""
+	}
+
+	return provider.Name()
+}
+
+func CreateCharge(charge Charge) (ChargeResult, error) {
+	if provider == nil {
+		return ChargeResult{}, ErrDisabled
+	}
+
+	return provider.CreateCharge(charge)
+}
+
+// ParseNotification verify the signature of a webhook request & return it's notification
+func ParseNotification(r *http.Request) (Notification, error) {
+	if provider == nil {
+		return Notification{}, ErrDisabled
+	}
+
+	return provider.ParseNotification(r)
+}
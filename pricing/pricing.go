@@ -0,0 +1,380 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Kind of a line item of a price breakdown
+const (
+	KindRent            = "rent"
+	KindDiscount        = "discount"
+	KindCleaningFee     = "cleaning_fee"
+	KindServiceFee      = "service_fee"
+	KindTax             = "tax"
+	KindSecurityDeposit = "security_deposit"
+)
+
+// Different types of error returned by the pricing functions
+var (
+	ErrUnknownTypeRent = errors.New("house has an unknown type of rent")
+	ErrPriceOverflow   = errors.New("total price is too large")
+)
+
+// maxTimeRent is the longest booking allowed for each type of rent
+var maxTimeRent = map[string]int{
+	"day":   90,
+	"month": 24,
+	"year":  5,
+}
+
+// Rule override the nightly price of a daily rental on the nights it match,
+// dates are formatted as "2006-01-02" & empty when the rule isn't limited to a date range
+type Rule struct {
+	Name      string
+	StartDate string
+	EndDate   string
+	Weekdays  []time.Weekday
+	Price     int64
+	MinStay   int
+}
+
+// Rates is the pricing of a rental plan of a house, discounts & tax rate are in percent
+type Rates struct {
+	TypeRent        string
+	Price           int64
+	MinTimeRent     int
+	MaxTimeRent     int
+	SecurityDeposit int64
+	CleaningFee     int64
+	ServiceFee      int64
+	WeeklyDiscount  int32
+	MonthlyDiscount int32
+	TaxRate         int32
+	Rules           []Rule
+}
+
+type LineItem struct {
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+	Quantity    int32  `json:"quantity"`
+	UnitPrice   int64  `json:"unit_price"`
+	Amount      int64  `json:"amount"`
+}
+
+// Quote is the itemised price of a booking
+type Quote struct {
+	CheckIn   time.Time  `json:"check_in"`
+	CheckOut  time.Time  `json:"check_out"`
+	TimeRent  int        `json:"time_rent"`
+	LineItems []LineItem `json:"line_items"`
+	Total     int64      `json:"total"`
+}
+
+// MaxTimeRent return the longest booking allowed for the type of rent, 0 for an unknown type of rent
+func MaxTimeRent(typeRent string) int {
+	return maxTimeRent[typeRent]
+}
+
+// ValidateTimeRent checks if the duration of a booking is allowed for the type of rent
+func ValidateTimeRent(typeRent string, timeRent int) error {
+	max, ok := maxTimeRent[typeRent]
+	if !ok {
+		return ErrUnknownTypeRent
+	}
+
+	if timeRent < 1 || timeRent > max {
+		return fmt.Errorf("time rent should be between 1 and %d %s", max, typeRent)
+	}
+
+	return nil
+}
+
+// validateRatesTimeRent checks if the duration of a booking is allowed by the rental plan
+func validateRatesTimeRent(rates Rates, timeRent int) error {
+	err := ValidateTimeRent(rates.TypeRent, timeRent)
+	if err != nil {
+		return err
+	}
+
+	if (rates.MinTimeRent > 0 && timeRent < rates.MinTimeRent) || (rates.MaxTimeRent > 0 && timeRent > rates.MaxTimeRent) {
+		return fmt.Errorf("time rent of this rental plan should be between %d and %d %s", rates.MinTimeRent, rates.MaxTimeRent, rates.TypeRent)
+	}
+
+	return nil
+}
+
+// CheckOut return the check out time of a booking
+func CheckOut(typeRent string, checkIn time.Time, timeRent int) (time.Time, error) {
+	switch typeRent {
+	case "day":
+		return checkIn.AddDate(0, 0, timeRent), nil
+	case "month":
+		return checkIn.AddDate(0, timeRent, 0), nil
+	case "year":
+		return checkIn.AddDate(timeRent, 0, 0), nil
+	}
+
+	return time.Time{}, ErrUnknownTypeRent
+}
+
+// Calculate return the itemised price of renting a house for timeRent units of it's type of rent.
+// Daily bookings of 7 nights get the weekly discount & of 28 nights get the monthly discount instead,
+// tax is charged on the rent & fees while the refundable security deposit is not taxed.
+func Calculate(rates Rates, checkIn time.Time, timeRent int) (Quote, error) {
+	err := validateRatesTimeRent(rates, timeRent)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	checkOut, err := CheckOut(rates.TypeRent, checkIn, timeRent)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	lineItems, err := rentOf(rates, checkIn, timeRent)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	rent, err := sum(lineItems)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	discountRate, discountName := discountOf(rates, timeRent)
+	discount, err := percentOf(rent, discountRate)
+	if err != nil {
+		return Quote{}, err
+	}
+	if discount > 0 {
+		lineItems = append(lineItems, LineItem{
+			Kind:        KindDiscount,
+			Description: fmt.Sprintf("%s discount (%d%%)", discountName, discountRate),
+			Quantity:    1,
+			UnitPrice:   -discount,
+			Amount:      -discount,
+		})
+	}
+
+	if rates.CleaningFee > 0 {
+		lineItems = append(lineItems, LineItem{
+			Kind:        KindCleaningFee,
+			Description: "Cleaning fee",
+			Quantity:    1,
+			UnitPrice:   rates.CleaningFee,
+			Amount:      rates.CleaningFee,
+		})
+	}
+
+	if rates.ServiceFee > 0 {
+		lineItems = append(lineItems, LineItem{
+			Kind:        KindServiceFee,
+			Description: "Service fee",
+			Quantity:    1,
+			UnitPrice:   rates.ServiceFee,
+			Amount:      rates.ServiceFee,
+		})
+	}
+
+	taxable, err := sum(lineItems)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	tax, err := percentOf(taxable, rates.TaxRate)
+	if err != nil {
+		return Quote{}, err
+	}
+	if tax > 0 {
+		lineItems = append(lineItems, LineItem{
+			Kind:        KindTax,
+			Description: fmt.Sprintf("Tax (%d%%)", rates.TaxRate),
+			Quantity:    1,
+			UnitPrice:   tax,
+			Amount:      tax,
+		})
+	}
+
+	if rates.SecurityDeposit > 0 {
+		lineItems = append(lineItems, LineItem{
+			Kind:        KindSecurityDeposit,
+			Description: "Refundable security deposit",
+			Quantity:    1,
+			UnitPrice:   rates.SecurityDeposit,
+			Amount:      rates.SecurityDeposit,
+		})
+	}
+
+	total, err := sum(lineItems)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	return Quote{
+		CheckIn:   checkIn,
+		CheckOut:  checkOut,
+		TimeRent:  timeRent,
+		LineItems: lineItems,
+		Total:     total,
+	}, nil
+}
+
+// rentOf return the rent line items of a booking. The nights of a daily rental are walked through
+// the price rules, each night is charged by the most specific matching rule or the house price
+func rentOf(rates Rates, checkIn time.Time, timeRent int) ([]LineItem, error) {
+	if rates.TypeRent != "day" || len(rates.Rules) == 0 {
+		rent, err := multiply(rates.Price, int64(timeRent))
+		if err != nil {
+			return nil, err
+		}
+
+		return []LineItem{{
+			Kind:        KindRent,
+			Description: fmt.Sprintf("Rent for %d %s", timeRent, rates.TypeRent),
+			Quantity:    int32(timeRent),
+			UnitPrice:   rates.Price,
+			Amount:      rent,
+		}}, nil
+	}
+
+	// nights are grouped by the rule charging them, -1 is the house price
+	nightsOf := make(map[int]int)
+	order := make([]int, 0)
+	for n := 0; n < timeRent; n++ {
+		night := checkIn.AddDate(0, 0, n)
+		ruleIndex := matchRule(rates.Rules, night)
+		if ruleIndex >= 0 && timeRent < rates.Rules[ruleIndex].MinStay {
+			rule := rates.Rules[ruleIndex]
+			return nil, fmt.Errorf("minimum stay for %s is %d nights", rule.Name, rule.MinStay)
+		}
+
+		if _, ok := nightsOf[ruleIndex]; !ok {
+			order = append(order, ruleIndex)
+		}
+		nightsOf[ruleIndex]++
+	}
+
+	lineItems := make([]LineItem, 0, len(order))
+	for _, ruleIndex := range order {
+		nights := nightsOf[ruleIndex]
+		price := rates.Price
+		description := fmt.Sprintf("Rent for %d day", nights)
+		if ruleIndex >= 0 {
+			price = rates.Rules[ruleIndex].Price
+			description = fmt.Sprintf("%s rent for %d day", rates.Rules[ruleIndex].Name, nights)
+		}
+
+		amount, err := multiply(price, int64(nights))
+		if err != nil {
+			return nil, err
+		}
+
+		lineItems = append(lineItems, LineItem{
+			Kind:        KindRent,
+			Description: description,
+			Quantity:    int32(nights),
+			UnitPrice:   price,
+			Amount:      amount,
+		})
+	}
+
+	return lineItems, nil
+}
+
+// matchRule return the index of the most specific rule matching a night, or -1 when no rule match it.
+// A rule with both a date range & weekdays beat a rule with a date range only, which beat a rule with weekdays only,
+// the latest rule win between rules as specific as each other
+func matchRule(rules []Rule, night time.Time) int {
+	date := night.Format("2006-01-02")
+	matched, matchedScore := -1, 0
+	for i, rule := range rules {
+		score := 0
+		if rule.StartDate != "" {
+			if date < rule.StartDate || date > rule.EndDate {
+				continue
+			}
+			score += 2
+		}
+
+		if len(rule.Weekdays) > 0 {
+			if !containsWeekday(rule.Weekdays, night.Weekday()) {
+				continue
+			}
+			score++
+		}
+
+		if score > 0 && score >= matchedScore {
+			matched, matchedScore = i, score
+		}
+	}
+
+	return matched
+}
+
+func containsWeekday(weekdays []time.Weekday, weekday time.Weekday) bool {
+	for _, v := range weekdays {
+		if v == weekday {
+			return true
+		}
+	}
+
+	return false
+}
+
+// discountOf return the discount rate applied to a booking & it's name
+func discountOf(rates Rates, timeRent int) (int32, string) {
+	if rates.TypeRent != "day" {
+		return 0, ""
+	}
+
+	if timeRent >= 28 && rates.MonthlyDiscount > 0 {
+		return rates.MonthlyDiscount, "Monthly"
+	}
+
+	if timeRent >= 7 && rates.WeeklyDiscount > 0 {
+		return rates.WeeklyDiscount, "Weekly"
+	}
+
+	return 0, ""
+}
+
+// percentOf return percent% of amount, rounded half up
+func percentOf(amount int64, percent int32) (int64, error) {
+	if percent <= 0 || amount <= 0 {
+		return 0, nil
+	}
+
+	value, err := multiply(amount, int64(percent))
+	if err != nil {
+		return 0, err
+	}
+
+	return (value + 50) / 100, nil
+}
+
+func multiply(a int64, b int64) (int64, error) {
+	if a < 0 || b < 0 {
+		return 0, errors.New("price should not be negative")
+	}
+
+	if b != 0 && a > math.MaxInt64/b {
+		return 0, ErrPriceOverflow
+	}
+
+	return a * b, nil
+}
+
+func sum(lineItems []LineItem) (int64, error) {
+	var total int64
+	for _, v := range lineItems {
+		if v.Amount > 0 && total > math.MaxInt64-v.Amount {
+			return 0, ErrPriceOverflow
+		}
+		total += v.Amount
+	}
+
+	return total, nil
+}
@@ -0,0 +1,418 @@
+package pricing
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(value string) time.Time {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCalculate(t *testing.T) {
+	checkIn := date("2024-05-01")
+
+	tests := []struct {
+		name         string
+		rates        Rates
+		timeRent     int
+		wantCheckOut time.Time
+		wantItems    []LineItem
+		wantTotal    int64
+	}{
+		{
+			name:         "monthly rent",
+			rates:        Rates{TypeRent: "month", Price: 1000000},
+			timeRent:     3,
+			wantCheckOut: date("2024-08-01"),
+			wantItems: []LineItem{
+				{Kind: KindRent, Description: "Rent for 3 month", Quantity: 3, UnitPrice: 1000000, Amount: 3000000},
+			},
+			wantTotal: 3000000,
+		},
+		{
+			name:         "monthly rent gets no daily discount",
+			rates:        Rates{TypeRent: "month", Price: 1000000, WeeklyDiscount: 10, MonthlyDiscount: 20},
+			timeRent:     12,
+			wantCheckOut: date("2025-05-01"),
+			wantItems: []LineItem{
+				{Kind: KindRent, Description: "Rent for 12 month", Quantity: 12, UnitPrice: 1000000, Amount: 12000000},
+			},
+			wantTotal: 12000000,
+		},
+		{
+			name:         "6 nights are below the weekly discount",
+			rates:        Rates{TypeRent: "day", Price: 100000, WeeklyDiscount: 10},
+			timeRent:     6,
+			wantCheckOut: date("2024-05-07"),
+			wantItems: []LineItem{
+				{Kind: KindRent, Description: "Rent for 6 day", Quantity: 6, UnitPrice: 100000, Amount: 600000},
+			},
+			wantTotal: 600000,
+		},
+		{
+			name:         "7 nights get the weekly discount",
+			rates:        Rates{TypeRent: "day", Price: 100000, WeeklyDiscount: 10, MonthlyDiscount: 20},
+			timeRent:     7,
+			wantCheckOut: date("2024-05-08"),
+			wantItems: []LineItem{
+				{Kind: KindRent, Description: "Rent for 7 day", Quantity: 7, UnitPrice: 100000, Amount: 700000},
+				{Kind: KindDiscount, Description: "Weekly discount (10%)", Quantity: 1, UnitPrice: -70000, Amount: -70000},
+			},
+			wantTotal: 630000,
+		},
+		{
+			name:         "28 nights get the monthly discount instead",
+			rates:        Rates{TypeRent: "day", Price: 100000, WeeklyDiscount: 10, MonthlyDiscount: 20},
+			timeRent:     28,
+			wantCheckOut: date("2024-05-29"),
+			wantItems: []LineItem{
+				{Kind: KindRent, Description: "Rent for 28 day", Quantity: 28, UnitPrice: 100000, Amount: 2800000},
+				{Kind: KindDiscount, Description: "Monthly discount (20%)", Quantity: 1, UnitPrice: -560000, Amount: -560000},
+			},
+			wantTotal: 2240000,
+		},
+		{
+			name:         "28 nights fall back to the weekly discount without a monthly one",
+			rates:        Rates{TypeRent: "day", Price: 100000, WeeklyDiscount: 10},
+			timeRent:     28,
+			wantCheckOut: date("2024-05-29"),
+			wantItems: []LineItem{
+				{Kind: KindRent, Description: "Rent for 28 day", Quantity: 28, UnitPrice: 100000, Amount: 2800000},
+				{Kind: KindDiscount, Description: "Weekly discount (10%)", Quantity: 1, UnitPrice: -280000, Amount: -280000},
+			},
+			wantTotal: 2520000,
+		},
+		{
+			name: "fees are taxed while the deposit isn't",
+			rates: Rates{
+				TypeRent:        "day",
+				Price:           100000,
+				CleaningFee:     50000,
+				ServiceFee:      25000,
+				TaxRate:         11,
+				SecurityDeposit: 500000,
+			},
+			timeRent:     2,
+			wantCheckOut: date("2024-05-03"),
+			wantItems: []LineItem{
+				{Kind: KindRent, Description: "Rent for 2 day", Quantity: 2, UnitPrice: 100000, Amount: 200000},
+				{Kind: KindCleaningFee, Description: "Cleaning fee", Quantity: 1, UnitPrice: 50000, Amount: 50000},
+				{Kind: KindServiceFee, Description: "Service fee", Quantity: 1, UnitPrice: 25000, Amount: 25000},
+				{Kind: KindTax, Description: "Tax (11%)", Quantity: 1, UnitPrice: 30250, Amount: 30250},
+				{Kind: KindSecurityDeposit, Description: "Refundable security deposit", Quantity: 1, UnitPrice: 500000, Amount: 500000},
+			},
+			wantTotal: 805250,
+		},
+		{
+			name:         "tax is charged on the discounted rent",
+			rates:        Rates{TypeRent: "day", Price: 100000, WeeklyDiscount: 10, TaxRate: 10},
+			timeRent:     7,
+			wantCheckOut: date("2024-05-08"),
+			wantItems: []LineItem{
+				{Kind: KindRent, Description: "Rent for 7 day", Quantity: 7, UnitPrice: 100000, Amount: 700000},
+				{Kind: KindDiscount, Description: "Weekly discount (10%)", Quantity: 1, UnitPrice: -70000, Amount: -70000},
+				{Kind: KindTax, Description: "Tax (10%)", Quantity: 1, UnitPrice: 63000, Amount: 63000},
+			},
+			wantTotal: 693000,
+		},
+		{
+			name:         "half a rupiah of tax is rounded up",
+			rates:        Rates{TypeRent: "day", Price: 1005, TaxRate: 10},
+			timeRent:     1,
+			wantCheckOut: date("2024-05-02"),
+			wantItems: []LineItem{
+				{Kind: KindRent, Description: "Rent for 1 day", Quantity: 1, UnitPrice: 1005, Amount: 1005},
+				{Kind: KindTax, Description: "Tax (10%)", Quantity: 1, UnitPrice: 101, Amount: 101},
+			},
+			wantTotal: 1106,
+		},
+		{
+			name:         "less than half a rupiah of tax is rounded down",
+			rates:        Rates{TypeRent: "day", Price: 1004, TaxRate: 10},
+			timeRent:     1,
+			wantCheckOut: date("2024-05-02"),
+			wantItems: []LineItem{
+				{Kind: KindRent, Description: "Rent for 1 day", Quantity: 1, UnitPrice: 1004, Amount: 1004},
+				{Kind: KindTax, Description: "Tax (10%)", Quantity: 1, UnitPrice: 100, Amount: 100},
+			},
+			wantTotal: 1104,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := Calculate(tt.rates, checkIn, tt.timeRent)
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+
+			if !quote.CheckOut.Equal(tt.wantCheckOut) {
+				t.Errorf("CheckOut = %v, want %v", quote.CheckOut, tt.wantCheckOut)
+			}
+			if !reflect.DeepEqual(quote.LineItems, tt.wantItems) {
+				t.Errorf("LineItems = %+v, want %+v", quote.LineItems, tt.wantItems)
+			}
+			if quote.Total != tt.wantTotal {
+				t.Errorf("Total = %d, want %d", quote.Total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestCalculateError(t *testing.T) {
+	checkIn := date("2024-05-01")
+
+	tests := []struct {
+		name     string
+		rates    Rates
+		timeRent int
+		wantErr  error
+	}{
+		{
+			name:     "unknown type of rent",
+			rates:    Rates{TypeRent: "week", Price: 100000},
+			timeRent: 1,
+			wantErr:  ErrUnknownTypeRent,
+		},
+		{
+			name:     "no time rent",
+			rates:    Rates{TypeRent: "day", Price: 100000},
+			timeRent: 0,
+		},
+		{
+			name:     "longer than the type of rent allows",
+			rates:    Rates{TypeRent: "day", Price: 100000},
+			timeRent: 91,
+		},
+		{
+			name:     "shorter than the rental plan allows",
+			rates:    Rates{TypeRent: "month", Price: 100000, MinTimeRent: 3, MaxTimeRent: 12},
+			timeRent: 2,
+		},
+		{
+			name:     "longer than the rental plan allows",
+			rates:    Rates{TypeRent: "month", Price: 100000, MinTimeRent: 3, MaxTimeRent: 12},
+			timeRent: 13,
+		},
+		{
+			name:     "rent overflows",
+			rates:    Rates{TypeRent: "day", Price: math.MaxInt64},
+			timeRent: 2,
+			wantErr:  ErrPriceOverflow,
+		},
+		{
+			name:     "fees overflow the total",
+			rates:    Rates{TypeRent: "day", Price: math.MaxInt64, CleaningFee: 1},
+			timeRent: 1,
+			wantErr:  ErrPriceOverflow,
+		},
+		{
+			name:     "tax overflows",
+			rates:    Rates{TypeRent: "day", Price: math.MaxInt64 / 50, TaxRate: 100},
+			timeRent: 1,
+			wantErr:  ErrPriceOverflow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Calculate(tt.rates, checkIn, tt.timeRent)
+			if err == nil {
+				t.Fatal("Calculate() error = nil, want an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Calculate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCalculateRules(t *testing.T) {
+	rates := Rates{
+		TypeRent: "day",
+		Price:    100000,
+		Rules: []Rule{
+			{Name: "Weekend", Weekdays: []time.Weekday{time.Friday, time.Saturday}, Price: 150000},
+			{Name: "Holiday", StartDate: "2024-05-04", EndDate: "2024-05-05", Price: 200000},
+			{Name: "Holiday weekend", StartDate: "2024-05-04", EndDate: "2024-05-04", Weekdays: []time.Weekday{time.Saturday}, Price: 300000},
+		},
+	}
+
+	// thursday to monday, the saturday is matched by all three rules
+	quote, err := Calculate(rates, date("2024-05-02"), 4)
+	if err != nil {
+		t.Fatalf("Calculate() error = %v", err)
+	}
+
+	wantItems := []LineItem{
+		{Kind: KindRent, Description: "Rent for 1 day", Quantity: 1, UnitPrice: 100000, Amount: 100000},
+		{Kind: KindRent, Description: "Weekend rent for 1 day", Quantity: 1, UnitPrice: 150000, Amount: 150000},
+		{Kind: KindRent, Description: "Holiday weekend rent for 1 day", Quantity: 1, UnitPrice: 300000, Amount: 300000},
+		{Kind: KindRent, Description: "Holiday rent for 1 day", Quantity: 1, UnitPrice: 200000, Amount: 200000},
+	}
+	if !reflect.DeepEqual(quote.LineItems, wantItems) {
+		t.Errorf("LineItems = %+v, want %+v", quote.LineItems, wantItems)
+	}
+	if quote.Total != 750000 {
+		t.Errorf("Total = %d, want %d", quote.Total, 750000)
+	}
+}
+
+func TestCalculateMinStay(t *testing.T) {
+	rates := Rates{
+		TypeRent: "day",
+		Price:    100000,
+		Rules: []Rule{
+			{Name: "Lebaran", StartDate: "2024-04-08", EndDate: "2024-04-12", Price: 250000, MinStay: 3},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		checkIn  time.Time
+		timeRent int
+		wantErr  bool
+	}{
+		{name: "shorter stay matching the rule", checkIn: date("2024-04-10"), timeRent: 2, wantErr: true},
+		{name: "long enough stay matching the rule", checkIn: date("2024-04-10"), timeRent: 3},
+		{name: "shorter stay outside the rule", checkIn: date("2024-04-20"), timeRent: 2},
+		{name: "stay ending before the rule", checkIn: date("2024-04-06"), timeRent: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Calculate(rates, tt.checkIn, tt.timeRent)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Calculate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatchRule(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []Rule
+		night time.Time
+		want  int
+	}{
+		{
+			name:  "no rule",
+			night: date("2024-05-04"),
+			want:  -1,
+		},
+		{
+			name:  "outside the date range",
+			rules: []Rule{{StartDate: "2024-05-01", EndDate: "2024-05-03"}},
+			night: date("2024-05-04"),
+			want:  -1,
+		},
+		{
+			name:  "the end date is included",
+			rules: []Rule{{StartDate: "2024-05-01", EndDate: "2024-05-04"}},
+			night: date("2024-05-04"),
+			want:  0,
+		},
+		{
+			name:  "another weekday",
+			rules: []Rule{{Weekdays: []time.Weekday{time.Sunday}}},
+			night: date("2024-05-04"),
+			want:  -1,
+		},
+		{
+			name: "date range beats weekdays",
+			rules: []Rule{
+				{StartDate: "2024-05-01", EndDate: "2024-05-31"},
+				{Weekdays: []time.Weekday{time.Saturday}},
+			},
+			night: date("2024-05-04"),
+			want:  0,
+		},
+		{
+			name: "date range & weekdays beat date range",
+			rules: []Rule{
+				{StartDate: "2024-05-01", EndDate: "2024-05-31", Weekdays: []time.Weekday{time.Saturday}},
+				{StartDate: "2024-05-04", EndDate: "2024-05-04"},
+			},
+			night: date("2024-05-04"),
+			want:  0,
+		},
+		{
+			name: "the latest rule wins a tie",
+			rules: []Rule{
+				{Weekdays: []time.Weekday{time.Saturday}},
+				{Weekdays: []time.Weekday{time.Friday, time.Saturday}},
+			},
+			night: date("2024-05-04"),
+			want:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchRule(tt.rules, tt.night); got != tt.want {
+				t.Errorf("matchRule() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPercentOf(t *testing.T) {
+	tests := []struct {
+		amount  int64
+		percent int32
+		want    int64
+		wantErr error
+	}{
+		{amount: 1000, percent: 10, want: 100},
+		{amount: 1005, percent: 10, want: 101},
+		{amount: 1004, percent: 10, want: 100},
+		{amount: 1000, percent: 0, want: 0},
+		{amount: -1000, percent: 10, want: 0},
+		{amount: math.MaxInt64, percent: 2, wantErr: ErrPriceOverflow},
+	}
+
+	for _, tt := range tests {
+		got, err := percentOf(tt.amount, tt.percent)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("percentOf(%d, %d) error = %v, want %v", tt.amount, tt.percent, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("percentOf(%d, %d) = %d, want %d", tt.amount, tt.percent, got, tt.want)
+		}
+	}
+}
+
+func TestMultiply(t *testing.T) {
+	tests := []struct {
+		a, b    int64
+		want    int64
+		wantErr bool
+	}{
+		{a: 100000, b: 7, want: 700000},
+		{a: math.MaxInt64, b: 1, want: math.MaxInt64},
+		{a: math.MaxInt64, b: 0, want: 0},
+		{a: math.MaxInt64/2 + 1, b: 2, wantErr: true},
+		{a: -1, b: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := multiply(tt.a, tt.b)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("multiply(%d, %d) error = %v, wantErr %v", tt.a, tt.b, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("multiply(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package pricing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	db "gubuk-service/db"
+	sqlc "gubuk-service/db/sqlc"
+
+	"github.com/google/uuid"
+)
+
+// ErrRentalPlanNotFound is returned when the house doesn't offer the requested rental plan
+var ErrRentalPlanNotFound = errors.New("rental plan with the provided id is not exist")
+
+// RatesOf return the pricing of renting a house through one of it's rental plans
+func RatesOf(house sqlc.GetHouseByIdRow, plan sqlc.HouseRentalPlan, rules []sqlc.HousePriceRule) Rates {
+	rates := Rates{
+		TypeRent:        plan.Unit,
+		Price:           plan.Price,
+		MinTimeRent:     int(plan.MinDuration),
+		MaxTimeRent:     int(plan.MaxDuration),
+		SecurityDeposit: house.SecurityDeposit,
+		CleaningFee:     house.CleaningFee,
+		ServiceFee:      house.ServiceFee,
+		WeeklyDiscount:  house.WeeklyDiscount,
+		MonthlyDiscount: house.MonthlyDiscount,
+		TaxRate:         house.TaxRate,
+	}
+
+	for _, v := range rules {
+		rule := Rule{
+			Name:    v.Name,
+			Price:   v.Price,
+			MinStay: int(v.MinStay),
+		}
+		if v.StartDate.Valid && v.EndDate.Valid {
+			rule.StartDate = v.StartDate.Time.Format("2006-01-02")
+			rule.EndDate = v.EndDate.Time.Format("2006-01-02")
+		}
+		rule.Weekdays, _ = ParseWeekdays(v.Weekdays)
+
+		rates.Rules = append(rates.Rules, rule)
+	}
+
+	return rates
+}
+
+// ParseWeekdays parse comma separated weekdays, 0 is sunday & 6 is saturday
+// ParseWeekdays("5,6") -> [friday saturday]
+func ParseWeekdays(weekdays string) ([]time.Weekday, error) {
+	if weekdays == "" {
+		return nil, nil
+	}
+
+	parsedWeekdays := make([]time.Weekday, 0)
+	for _, v := range strings.Split(weekdays, ",") {
+		weekday, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || weekday < 0 || weekday > 6 {
+			return nil, errors.New("weekdays should be comma separated numbers between 0 (sunday) and 6 (saturday)")
+		}
+		parsedWeekdays = append(parsedWeekdays, time.Weekday(weekday))
+	}
+
+	return parsedWeekdays, nil
+}
+
+// LoadRates return the pricing of renting a house through the rental plan with the provided id,
+// or through the plan of the house's type of rent when no id is provided
+func LoadRates(house sqlc.GetHouseByIdRow, rentalPlanID string) (Rates, error) {
+	var plan sqlc.HouseRentalPlan
+	var err error
+	if rentalPlanID != "" {
+		planID, parseErr := uuid.Parse(rentalPlanID)
+		if parseErr != nil {
+			return Rates{}, ErrRentalPlanNotFound
+		}
+		plan, err = db.Queries.GetHouseRentalPlanById(context.TODO(), planID)
+	} else {
+		plan, err = db.Queries.GetHouseRentalPlanByUnit(context.TODO(), sqlc.GetHouseRentalPlanByUnitParams{
+			HouseID: house.ID,
+			Unit:    house.TypeRent,
+		})
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return Rates{}, ErrRentalPlanNotFound
+	}
+	if err != nil {
+		return Rates{}, err
+	}
+
+	// a plan of another house is as not exist as a missing one
+	if plan.HouseID != house.ID {
+		return Rates{}, ErrRentalPlanNotFound
+	}
+
+	rules, err := db.Queries.ListHousePriceRule(context.TODO(), house.ID)
+	if err != nil {
+		return Rates{}, err
+	}
+
+	return RatesOf(house, plan, rules), nil
+}
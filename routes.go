@@ -1,9 +1,16 @@
 package main
 
 import (
+	"gubuk-service/domain/admin"
 	"gubuk-service/domain/house"
+	"gubuk-service/domain/idempotency"
+	"gubuk-service/domain/message"
+	"gubuk-service/domain/notification"
+	"gubuk-service/domain/review"
 	"gubuk-service/domain/transaction"
 	"gubuk-service/domain/user"
+	"gubuk-service/domain/webhook"
+	"gubuk-service/payment"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,20 +28,102 @@ func SetRoutes(router *gin.Engine) {
 	apiGroup.PATCH("/user/avatar", user.VerifyAuth, user.UpdateUserAvatar)
 	apiGroup.PATCH("/user/password", user.VerifyAuth, user.UpdateUserPassword)
 	apiGroup.PATCH("/user", user.VerifyAuth, user.UpdateUserProfile)
+	apiGroup.PATCH("/user/privacy", user.VerifyAuth, user.UpdateUserPrivacy)
 	apiGroup.GET("/user", user.VerifyAuth, user.GetUserDetail)
+	apiGroup.DELETE("/user", user.VerifyAuth, user.DeleteUser)
+	apiGroup.GET("/user/export", user.VerifyAuth, user.ExportUserData)
+	apiGroup.POST("/user/roles/owner", user.VerifyAuth, user.VerifyRole("tenant"), user.UpgradeToOwner)
 
 	// House
-	apiGroup.POST("/houses", user.VerifyAuth, user.VerifyRole("owner"), house.CreateHouse)
-	apiGroup.PATCH("/houses/:id", user.VerifyAuth, user.VerifyRole("owner"), house.UpdateHouse)
-	apiGroup.DELETE("/houses/:id", user.VerifyAuth, user.VerifyRole("owner"), house.DeleteHouse)
-	apiGroup.GET("/houses", house.GetHouseList)
-	apiGroup.GET("/houses/me", user.VerifyAuth, user.VerifyRole("owner"), house.GetMyHouseList)
-	apiGroup.GET("/houses/:id", house.GetHouseDetail)
+	apiGroup.POST("/houses", user.VerifyAuth, user.VerifyPermission(user.PermissionHouseManage), house.CreateHouse)
+	apiGroup.PATCH("/houses/:id", user.VerifyAuth, user.VerifyPermission(user.PermissionHouseManage), house.UpdateHouse)
+	apiGroup.DELETE("/houses/:id", user.VerifyAuth, user.VerifyPermission(user.PermissionHouseManage), house.DeleteHouse)
+	apiGroup.PATCH("/houses/:id/restore", user.VerifyAuth, user.VerifyPermission(user.PermissionHouseManage), house.RestoreHouse)
+	apiGroup.PATCH("/houses/:id/status", user.VerifyAuth, user.VerifyPermission(user.PermissionHouseManage), house.UpdateHouseStatus)
+	apiGroup.GET("/houses", user.OptionalAuth, house.GetHouseList)
+	apiGroup.GET("/houses/me", user.VerifyAuth, user.VerifyPermission(user.PermissionHouseManage), house.GetMyHouseList)
+	apiGroup.POST("/houses/imports", user.VerifyAuth, user.VerifyPermission(user.PermissionHouseManage), house.ImportHouse)
+	apiGroup.GET("/houses/imports/:id", user.VerifyAuth, user.VerifyPermission(user.PermissionHouseManage), house.GetHouseImport)
+	apiGroup.GET("/houses/:id", user.OptionalAuth, house.GetHouseDetail)
 	apiGroup.GET("/houses/count", house.GetHouseCount)
+	apiGroup.GET("/houses/:id/quote", user.OptionalAuth, house.GetHouseQuote)
+	apiGroup.GET("/houses/:id/rental-plans", user.OptionalAuth, house.ListHouseRentalPlan)
+	apiGroup.PUT("/houses/:id/rental-plans", user.VerifyAuth, user.VerifyPermission(user.PermissionHouseManage), house.SaveHouseRentalPlan)
+	apiGroup.DELETE("/houses/:id/rental-plans/:plan_id", user.VerifyAuth, user.VerifyPermission(user.PermissionHouseManage), house.DeleteHouseRentalPlan)
+	apiGroup.GET("/houses/:id/price-rules", user.OptionalAuth, house.ListHousePriceRule)
+	apiGroup.POST("/houses/:id/price-rules", user.VerifyAuth, user.VerifyPermission(user.PermissionHouseManage), house.CreateHousePriceRule)
+	apiGroup.DELETE("/houses/:id/price-rules/:rule_id", user.VerifyAuth, user.VerifyPermission(user.PermissionHouseManage), house.DeleteHousePriceRule)
+
+	// Favorite
+	apiGroup.GET("/favorites", user.VerifyAuth, user.VerifyPermission(user.PermissionFavoriteManage), house.GetFavoriteHouseList)
+	apiGroup.POST("/houses/:id/favorite", user.VerifyAuth, user.VerifyPermission(user.PermissionFavoriteManage), house.AddFavoriteHouse)
+	apiGroup.DELETE("/houses/:id/favorite", user.VerifyAuth, user.VerifyPermission(user.PermissionFavoriteManage), house.DeleteFavoriteHouse)
+
+	// Saved search
+	apiGroup.GET("/saved-searches", user.VerifyAuth, user.VerifyRole("tenant"), house.ListSavedSearch)
+	apiGroup.POST("/saved-searches", user.VerifyAuth, user.VerifyRole("tenant"), house.CreateSavedSearch)
+	apiGroup.DELETE("/saved-searches/:id", user.VerifyAuth, user.VerifyRole("tenant"), house.DeleteSavedSearch)
+	apiGroup.GET("/saved-searches/:id/matches", user.VerifyAuth, user.VerifyRole("tenant"), house.ListSavedSearchMatch)
+
+	// Owner
+	apiGroup.GET("/owners/:username", user.OptionalAuth, house.GetOwnerProfile)
+	apiGroup.GET("/owner/stats", user.VerifyAuth, user.VerifyPermission(user.PermissionHouseManage), house.GetOwnerStats)
 
 	// Transaction
-	apiGroup.POST("/transactions", user.VerifyAuth, user.VerifyRole("tenant"), transaction.CreateTransaction)
+	apiGroup.POST("/transactions", user.VerifyAuth, user.VerifyPermission(user.PermissionTransactionCreate), idempotency.VerifyKey, transaction.CreateTransaction)
 	apiGroup.GET("/transactions", user.VerifyAuth, transaction.ListTransaction)
-	apiGroup.PATCH("/transactions/pay/:id", user.VerifyAuth, user.VerifyRole("tenant"), transaction.PayTransaction)
-	apiGroup.PATCH("/transactions/status/:id", user.VerifyAuth, user.VerifyRole("owner"), transaction.UpdateTransactionStatus)
+	apiGroup.GET("/transactions/export", user.VerifyAuth, transaction.ExportTransaction)
+	apiGroup.GET("/transactions/:id", user.VerifyAuth, transaction.GetTransactionDetail)
+	apiGroup.PATCH("/transactions/pay/:id", user.VerifyAuth, user.VerifyPermission(user.PermissionTransactionCreate), idempotency.VerifyKey, transaction.PayTransaction)
+	apiGroup.GET("/transactions/:id/payment-proof", user.VerifyAuth, transaction.GetTransactionPaymentProof)
+	apiGroup.GET("/transactions/:id/payment-submissions", user.VerifyAuth, transaction.ListPaymentSubmission)
+	apiGroup.GET("/transactions/:id/payment-submissions/:submission_id/payment-proof", user.VerifyAuth, transaction.GetPaymentSubmissionProof)
+	apiGroup.PATCH("/transactions/:id/payment-submissions/:submission_id/reject", user.VerifyAuth, user.VerifyPermission(user.PermissionTransactionManage), transaction.RejectPaymentSubmission)
+	apiGroup.PATCH("/transactions/status/:id", user.VerifyAuth, user.VerifyPermission(user.PermissionTransactionManage), transaction.UpdateTransactionStatus)
+
+	// Review
+	apiGroup.POST("/transactions/:id/review", user.VerifyAuth, user.VerifyPermission(user.PermissionReviewCreate), review.CreateReview)
+	apiGroup.GET("/houses/:id/reviews", user.OptionalAuth, review.ListHouseReview)
+	apiGroup.PATCH("/reviews/:id/reply", user.VerifyAuth, user.VerifyPermission(user.PermissionReviewReply), review.ReplyReview)
+
+	// Messaging
+	apiGroup.POST("/conversations", user.VerifyAuth, user.VerifyAnyRole("tenant", "owner"), message.CreateConversation)
+	apiGroup.GET("/conversations", user.VerifyAuth, message.ListConversation)
+	apiGroup.GET("/conversations/:id/messages", user.VerifyAuth, message.ListMessage)
+	apiGroup.POST("/conversations/:id/messages", user.VerifyAuth, message.SendMessage)
+	apiGroup.PATCH("/conversations/:id/read", user.VerifyAuth, message.ReadConversation)
+
+	// Notification
+	apiGroup.GET("/notifications", user.VerifyAuth, notification.ListNotification)
+	apiGroup.GET("/notifications/stream", user.VerifyAuth, notification.StreamNotification)
+	apiGroup.PATCH("/notifications/read", user.VerifyAuth, notification.ReadAllNotification)
+	apiGroup.PATCH("/notifications/:id/read", user.VerifyAuth, notification.ReadNotification)
+	apiGroup.GET("/user/notification-preferences", user.VerifyAuth, notification.GetNotificationPreference)
+	apiGroup.PUT("/user/notification-preferences", user.VerifyAuth, notification.UpdateNotificationPreference)
+
+	// Webhook
+	apiGroup.POST("/webhooks", user.VerifyAuth, user.VerifyPermission(user.PermissionWebhookManage), webhook.CreateWebhook)
+	apiGroup.GET("/webhooks", user.VerifyAuth, user.VerifyPermission(user.PermissionWebhookManage), webhook.ListWebhook)
+	apiGroup.DELETE("/webhooks/:id", user.VerifyAuth, user.VerifyPermission(user.PermissionWebhookManage), webhook.DeleteWebhook)
+	apiGroup.GET("/webhooks/:id/deliveries", user.VerifyAuth, user.VerifyPermission(user.PermissionWebhookManage), webhook.ListWebhookDelivery)
+	apiGroup.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", user.VerifyAuth, user.VerifyPermission(user.PermissionWebhookManage), webhook.RedeliverWebhookDelivery)
+	apiGroup.POST("/webhooks/echo", webhook.EchoWebhook)
+
+	// Admin
+	apiGroup.GET("/admin/users", user.VerifyAuth, user.VerifyPermission(user.PermissionUserModerate), admin.ListUser)
+	apiGroup.PATCH("/admin/users/:id/suspend", user.VerifyAuth, user.VerifyPermission(user.PermissionUserModerate), admin.SuspendUser)
+	apiGroup.PATCH("/admin/users/:id/unsuspend", user.VerifyAuth, user.VerifyPermission(user.PermissionUserModerate), admin.UnsuspendUser)
+	apiGroup.GET("/admin/houses", user.VerifyAuth, user.VerifyPermission(user.PermissionHouseModerate), admin.ListHouse)
+	apiGroup.PATCH("/admin/houses/:id/unpublish", user.VerifyAuth, user.VerifyPermission(user.PermissionHouseModerate), admin.UnpublishHouse)
+	apiGroup.PATCH("/admin/houses/:id/publish", user.VerifyAuth, user.VerifyPermission(user.PermissionHouseModerate), admin.PublishHouse)
+	apiGroup.PATCH("/admin/houses/:id/reject", user.VerifyAuth, user.VerifyPermission(user.PermissionHouseModerate), admin.RejectHouse)
+	apiGroup.DELETE("/admin/houses/:id", user.VerifyAuth, user.VerifyPermission(user.PermissionHouseModerate), admin.DeleteHouse)
+	apiGroup.GET("/admin/transactions", user.VerifyAuth, user.VerifyPermission(user.PermissionTransactionModerate), transaction.ListTransaction)
+	apiGroup.PATCH("/admin/transactions/:id/resolve", user.VerifyAuth, user.VerifyPermission(user.PermissionTransactionModerate), admin.ResolveTransaction)
+	apiGroup.GET("/admin/audit-logs", user.VerifyAuth, user.VerifyPermission(user.PermissionAuditView), admin.ListAuditLog)
+
+	// Payment Gateway
+	apiGroup.POST("/transactions/:id/charge", user.VerifyAuth, user.VerifyPermission(user.PermissionTransactionCreate), idempotency.VerifyKey, transaction.CreateTransactionCharge)
+	apiGroup.POST("/payments/webhook", transaction.HandlePaymentWebhook)
+	apiGroup.GET("/payments/simulator/:id", payment.Simulate(transaction.HandlePaymentWebhook))
 }
@@ -6,6 +6,7 @@ import (
 	"log"
 
 	sqlc "gubuk-service/db/sqlc"
+	"gubuk-service/pricing"
 
 	"github.com/google/uuid"
 	_ "github.com/lib/pq"
@@ -28,17 +29,19 @@ func main() {
 
 	seedUser()
 	seedHome()
+	seedRentalPlan()
 }
 
 func seedUser() {
 	var err error
+	var createdUserID uuid.UUID
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("12345678"), bcrypt.DefaultCost)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	// user without avatar, with role as a tenant
-	_, err = testQueries.CreateUser(context.TODO(), sqlc.CreateUserParams{
+	createdUserID, err = testQueries.CreateUser(context.TODO(), sqlc.CreateUserParams{
 		ID:          uuid.New(),
 		Fullname:    "Febrian Amir",
 		Username:    "febrian",
@@ -54,8 +57,16 @@ func seedUser() {
 		log.Fatal(err)
 	}
 
+	err = testQueries.AddUserRole(context.TODO(), sqlc.AddUserRoleParams{
+		UserID:   createdUserID,
+		RoleName: "tenant",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// user with avatar, with role as an owner
-	_, err = testQueries.CreateUser(context.TODO(), sqlc.CreateUserParams{
+	createdUserID, err = testQueries.CreateUser(context.TODO(), sqlc.CreateUserParams{
 		ID:          ownerID,
 		Fullname:    "Amiruddin",
 		Username:    "amiruddin",
@@ -70,6 +81,16 @@ func seedUser() {
 	if err != nil {
 		log.Fatal(err)
 	}
+
+	err = testQueries.AddUserRole(context.TODO(), sqlc.AddUserRoleParams{
+		UserID:   createdUserID,
+		RoleName: "owner",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// there's no seeded admin, as it would be a known login, grant the role with cmd/promote instead
 }
 
 func seedHome() {
@@ -88,6 +109,7 @@ func seedHome() {
 		Description:   "Rumah Krong Bade dari Aceh ini berbentuk memanjang dari timur ke barat menyerupai persegi panjang. Di bagian depan rumah dilengkapi dengan tangga untuk masuk ke dalam rumah.",
 		Amenities:     "Furnished",
 		Area:          70,
+		Status:        "published",
 	})
 	if err != nil {
 		log.Fatal(err)
@@ -107,6 +129,7 @@ func seedHome() {
 		Description:   "Pada rumah adat Bolon ini, terdapat dua bagian yang berbeda, yaitu Jabu Bolon dan juga Jabu Parsakitan. Jabu Bolon biasa menjadi tempat untuk keluarga besar, sedangkan Jabu Parsakitan adalah tempat untuk membicarakan masalah adat.",
 		Amenities:     "Shared Accomodation",
 		Area:          60,
+		Status:        "published",
 	})
 	if err != nil {
 		log.Fatal(err)
@@ -126,6 +149,7 @@ func seedHome() {
 		Description:   "Rumah adat Gadang terbuat dari ijuk dan bentuknya mirip seperti tanduk kerbau, yang melambangkan kemenangan suku Minang dalam perlombaan adu kerbau di Jawa.",
 		Amenities:     "Pet Allowed",
 		Area:          54,
+		Status:        "published",
 	})
 	if err != nil {
 		log.Fatal(err)
@@ -145,6 +169,7 @@ func seedHome() {
 		Description:   "Rumah ini memiliki arti rumah dengan dua selasar. Masyarakat Riau tidak menjadikan Rumah Selaso Jatuh Kembar sebagai tempat tinggal mereka, tetapi hanya menggunakannya untuk acara adat.",
 		Amenities:     "Furnished,Pet Allowed",
 		Area:          45,
+		Status:        "published",
 	})
 	if err != nil {
 		log.Fatal(err)
@@ -164,6 +189,7 @@ func seedHome() {
 		Description:   "Rumah adat dari Bengkulu ini memiliki tiang penopang dan menggunakan kayu khusus untuk membuatnya, yaitu kayu Medang Kemuning. Untuk memasuki rumah ini, Anda juga harus menggunakan tangga, yang berada pada bagian depan rumah. ",
 		Amenities:     "Furnished,Shared Accomodation",
 		Area:          70,
+		Status:        "published",
 	})
 	if err != nil {
 		log.Fatal(err)
@@ -183,6 +209,7 @@ func seedHome() {
 		Description:   "Orang-orang sering menyebutkan bagian atap dari Rumah Panggung ini sebagai “Gajah Mabuk” karena bentuknya yang menyerupai perahu dengan ujung melengkung. Biasanya, rumah adat dari Jambi digunakan untuk tempat tinggal dan juga tempat bermusyawarah.",
 		Amenities:     "Shared Accomodation,Pet Allowed",
 		Area:          60,
+		Status:        "published",
 	})
 	if err != nil {
 		log.Fatal(err)
@@ -202,6 +229,7 @@ func seedHome() {
 		Description:   "Rumah adat Provinsi Lampung memiliki nama Nuwo Sesat. Ciri khas dari rumah ini adalah bentuknya panggung dan di sisi-sisinya terdapat ornamen yang khas. Biasanya, ukuran dari rumah ini sangat besar, tetapi saat ini banyak yang membuat Rumah Nuwo Sesat berukuran lebih kecil.",
 		Amenities:     "Furnished,Pet Allowed,Shared Accomodation",
 		Area:          54,
+		Status:        "published",
 	})
 	if err != nil {
 		log.Fatal(err)
@@ -221,6 +249,7 @@ func seedHome() {
 		Description:   "Rumah adat satu ini memiliki bentuk yang sesuai dengan namanya, yaitu menyerupai limas. Tamu yang berkunjung ke rumah ini harus singgah ke ruang atas atau teras rumah. Hal ini merupakan tradisi masyarakat Sumatera Selatan agar dapat merasakan budaya mereka, yang tampak pada ukiran di dalamnya.",
 		Amenities:     "Furnished,Pet Allowed,Shared Accomodation",
 		Area:          45,
+		Status:        "published",
 	})
 	if err != nil {
 		log.Fatal(err)
@@ -240,6 +269,7 @@ func seedHome() {
 		Description:   "Karena Bangka Belitung memiliki banyak yang tergenang air atau di tepi laut, warga setempat harus menyesuaikan diri, yaitu dengan membangun rumah di atas air juga yang dinamakan Rumah Rakit. Bentuk rumah adat provinsi Bangka belitung terlihat sangat unik karena merupakan perpaduan rumah Melayu dengan aksen arsitektur Tionghoa.",
 		Amenities:     "Furnished,Pet Allowed,Shared Accomodation",
 		Area:          70,
+		Status:        "published",
 	})
 	if err != nil {
 		log.Fatal(err)
@@ -259,8 +289,31 @@ func seedHome() {
 		Description:   "Rumah adat dari Kepulauan Riau ini terlihat sangat sederhana. Berbentuk seperti rumah panggung, yang memanjang ke belakang dengan dinding kayu tersusun secara vertikal.",
 		Amenities:     "Furnished,Shared Accomodation",
 		Area:          54,
+		Status:        "published",
 	})
 	if err != nil {
 		log.Fatal(err)
 	}
 }
+
+// seedRentalPlan create the primary rental plan of every seeded house from it's type of rent & price
+func seedRentalPlan() {
+	houses, err := testQueries.ListHouse(context.TODO())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, house := range houses {
+		_, err = testQueries.UpsertHouseRentalPlan(context.TODO(), sqlc.UpsertHouseRentalPlanParams{
+			ID:          uuid.New(),
+			HouseID:     house.ID,
+			Unit:        house.TypeRent,
+			Price:       house.Price,
+			MinDuration: 1,
+			MaxDuration: int32(pricing.MaxTimeRent(house.TypeRent)),
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
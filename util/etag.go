@@ -0,0 +1,42 @@
+package util
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrPreconditionFailed is returned when a resource is changed since the version a request is based on
+var ErrPreconditionFailed = errors.New("it's changed by another request since you fetched it, please fetch it again")
+
+// ETagOf return the entity tag of a resource, a resource is versioned by it's updated_at which should be
+// read from the database, as it's stored with a microsecond precision
+func ETagOf(updatedAt time.Time) string {
+	return `"` + strconv.FormatInt(updatedAt.UnixMicro(), 36) + `"`
+}
+
+// SetETag tell the client the version of the returned resource, to be sent back in the If-Match header
+func SetETag(c *gin.Context, updatedAt time.Time) {
+	c.Header("ETag", ETagOf(updatedAt))
+}
+
+// CheckIfMatch checks the If-Match header of a request against the current version of a resource,
+// a request without it is let through as a blind update
+func CheckIfMatch(c *gin.Context, updatedAt time.Time) error {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" || ifMatch == "*" {
+		return nil
+	}
+
+	etag := ETagOf(updatedAt)
+	for _, v := range strings.Split(ifMatch, ",") {
+		if strings.TrimSpace(v) == etag {
+			return nil
+		}
+	}
+
+	return ErrPreconditionFailed
+}
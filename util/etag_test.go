@@ -0,0 +1,76 @@
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestETagRoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// postgres keeps the microseconds of a timestamp, the nanoseconds of the time it's written with are dropped
+	written := time.Date(2024, 5, 1, 10, 30, 15, 123456789, time.UTC)
+	stored := written.Truncate(time.Microsecond)
+
+	tests := []struct {
+		name      string
+		ifMatch   func(etag string) string
+		updatedAt time.Time
+		wantErr   error
+	}{
+		{
+			name:      "etag of the written time matches the stored one",
+			ifMatch:   func(etag string) string { return etag },
+			updatedAt: stored,
+		},
+		{
+			name:      "etag in a list matches",
+			ifMatch:   func(etag string) string { return `"other", ` + etag },
+			updatedAt: stored,
+		},
+		{
+			name:      "update a microsecond later doesn't match",
+			ifMatch:   func(etag string) string { return etag },
+			updatedAt: stored.Add(time.Microsecond),
+			wantErr:   ErrPreconditionFailed,
+		},
+		{
+			name:      "unquoted etag doesn't match",
+			ifMatch:   func(etag string) string { return etag[1 : len(etag)-1] },
+			updatedAt: stored,
+			wantErr:   ErrPreconditionFailed,
+		},
+		{
+			name:      "wildcard matches any version",
+			ifMatch:   func(etag string) string { return "*" },
+			updatedAt: stored.Add(time.Hour),
+		},
+		{
+			name:      "missing If-Match is a blind update",
+			ifMatch:   func(etag string) string { return "" },
+			updatedAt: stored.Add(time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(res)
+			SetETag(c, written)
+
+			c.Request = httptest.NewRequest(http.MethodPatch, "/houses/1", nil)
+			if ifMatch := tt.ifMatch(res.Header().Get("ETag")); ifMatch != "" {
+				c.Request.Header.Set("If-Match", ifMatch)
+			}
+
+			err := CheckIfMatch(c, tt.updatedAt)
+			if err != tt.wantErr {
+				t.Errorf("CheckIfMatch() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Content types of a PATCH body which is applied as a JSON Merge Patch (RFC 7396)
+const (
+	MIMEMergePatch = "application/merge-patch+json"
+	MIMEJSON       = "application/json"
+)
+
+// BindPatch apply a partial update onto obj, which should already hold the current state of the resource,
+// so only the fields the client sent are changed, the merged request is validated as a whole afterward.
+// A form body simply leaves out the fields it doesn't change, while a JSON body is a merge patch where
+// a null removes the field (resets it to it's zero value)
+func BindPatch(c *gin.Context, obj interface{}) error {
+	switch c.ContentType() {
+	case MIMEMergePatch, MIMEJSON:
+		return bindMergePatch(c, obj)
+	default:
+		return c.ShouldBind(obj)
+	}
+}
+
+func bindMergePatch(c *gin.Context, obj interface{}) error {
+	var patch interface{}
+	decoder := json.NewDecoder(io.LimitReader(c.Request.Body, 1<<20))
+	decoder.UseNumber()
+	err := decoder.Decode(&patch)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := patch.(map[string]interface{}); !ok {
+		return errors.New("the merge patch should be a JSON object")
+	}
+
+	current, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	var target interface{}
+	decoder = json.NewDecoder(bytes.NewReader(current))
+	decoder.UseNumber()
+	err = decoder.Decode(&target)
+	if err != nil {
+		return err
+	}
+
+	merged, err := json.Marshal(mergePatch(target, patch))
+	if err != nil {
+		return err
+	}
+
+	// a removed field is left out of the merged document, so it's reset by decoding into a zero value
+	value := reflect.ValueOf(obj).Elem()
+	value.Set(reflect.Zero(value.Type()))
+
+	err = json.Unmarshal(merged, obj)
+	if err != nil {
+		return err
+	}
+
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// mergePatch apply a merge patch onto a target document as described by RFC 7396
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+
+	for k, v := range patchObject {
+		if v == nil {
+			delete(targetObject, k)
+			continue
+		}
+
+		targetObject[k] = mergePatch(targetObject[k], v)
+	}
+
+	return targetObject
+}
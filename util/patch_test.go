@@ -0,0 +1,121 @@
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type patchedRequest struct {
+	Title     string `form:"title" json:"title" binding:"required"`
+	Bedrooms  int    `form:"bedrooms" json:"bedrooms" binding:"required,min=1"`
+	Amenities string `form:"amenities" json:"amenities"`
+	Discount  int32  `form:"discount" json:"discount" binding:"omitempty,max=100"`
+}
+
+func TestBindPatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	RegisterFieldNames()
+
+	current := patchedRequest{Title: "Rumah Gubuk", Bedrooms: 2, Amenities: "wifi", Discount: 10}
+
+	tests := []struct {
+		name            string
+		contentType     string
+		body            string
+		want            patchedRequest
+		wantFieldErrors []FieldError
+		wantErr         bool
+	}{
+		{
+			name:        "absent fields are kept",
+			contentType: MIMEMergePatch,
+			body:        `{"bedrooms":3}`,
+			want:        patchedRequest{Title: "Rumah Gubuk", Bedrooms: 3, Amenities: "wifi", Discount: 10},
+		},
+		{
+			name:        "json is applied as a merge patch",
+			contentType: MIMEJSON,
+			body:        `{"title":"Rumah Baru"}`,
+			want:        patchedRequest{Title: "Rumah Baru", Bedrooms: 2, Amenities: "wifi", Discount: 10},
+		},
+		{
+			name:        "null resets an optional field",
+			contentType: MIMEMergePatch,
+			body:        `{"amenities":null,"discount":null}`,
+			want:        patchedRequest{Title: "Rumah Gubuk", Bedrooms: 2},
+		},
+		{
+			name:            "null of a required field is invalid",
+			contentType:     MIMEMergePatch,
+			body:            `{"title":null}`,
+			wantFieldErrors: []FieldError{{Field: "title", Message: "is required"}},
+			wantErr:         true,
+		},
+		{
+			name:        "unknown fields are ignored",
+			contentType: MIMEMergePatch,
+			body:        `{"owner_id":"someone-else","bedrooms":4}`,
+			want:        patchedRequest{Title: "Rumah Gubuk", Bedrooms: 4, Amenities: "wifi", Discount: 10},
+		},
+		{
+			name:            "type error is reported on it's field",
+			contentType:     MIMEMergePatch,
+			body:            `{"bedrooms":"three"}`,
+			wantFieldErrors: []FieldError{{Field: "bedrooms", Message: "should be int, not string"}},
+			wantErr:         true,
+		},
+		{
+			name:            "merged request is validated as a whole",
+			contentType:     MIMEMergePatch,
+			body:            `{"bedrooms":0,"discount":150}`,
+			wantFieldErrors: []FieldError{{Field: "bedrooms", Message: "is required"}, {Field: "discount", Message: "should be at most 100"}},
+			wantErr:         true,
+		},
+		{
+			name:        "patch which isn't an object is refused",
+			contentType: MIMEMergePatch,
+			body:        `["title"]`,
+			wantErr:     true,
+		},
+		{
+			name:        "absent form fields are kept",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "title=Rumah+Baru",
+			want:        patchedRequest{Title: "Rumah Baru", Bedrooms: 2, Amenities: "wifi", Discount: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPatch, "/houses/1", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", tt.contentType)
+
+			got := current
+			err := BindPatch(c, &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BindPatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				fieldErrors := FieldErrorsOf(err)
+				if len(tt.wantFieldErrors) == 0 && len(fieldErrors) == 0 {
+					return
+				}
+				if !reflect.DeepEqual(fieldErrors, tt.wantFieldErrors) {
+					t.Errorf("FieldErrorsOf() = %v, want %v", fieldErrors, tt.wantFieldErrors)
+				}
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("BindPatch() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -11,6 +11,8 @@ type response struct {
 	Status string      `json:"status"`
 	Data   interface{} `json:"data,omitempty"`
 	Error  string      `json:"error,omitempty"`
+	// Errors are the invalid fields of a request, see SendValidationError
+	Errors []FieldError `json:"errors,omitempty"`
 }
 
 func SendSuccess(c *gin.Context, data interface{}) {
@@ -56,3 +58,39 @@ func SendNotFound(c *gin.Context, err error) {
 	})
 	c.Abort()
 }
+
+func SendConflict(c *gin.Context, err error) {
+	c.JSON(http.StatusConflict, response{
+		Code:   409,
+		Status: "CONFLICT",
+		Error:  err.Error(),
+	})
+	c.Abort()
+}
+
+func SendUnprocessableEntity(c *gin.Context, err error) {
+	c.JSON(http.StatusUnprocessableEntity, response{
+		Code:   422,
+		Status: "UNPROCESSABLE ENTITY",
+		Error:  err.Error(),
+	})
+	c.Abort()
+}
+
+func SendPreconditionFailed(c *gin.Context, err error) {
+	c.JSON(http.StatusPreconditionFailed, response{
+		Code:   412,
+		Status: "PRECONDITION FAILED",
+		Error:  err.Error(),
+	})
+	c.Abort()
+}
+
+func SendRequestEntityTooLarge(c *gin.Context, err error) {
+	c.JSON(http.StatusRequestEntityTooLarge, response{
+		Code:   413,
+		Status: "REQUEST ENTITY TOO LARGE",
+		Error:  err.Error(),
+	})
+	c.Abort()
+}
@@ -22,6 +22,11 @@ type UserPayload struct {
 	UserRole  string    `json:"user_role"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
+
+	// roles & permissions are loaded from the database on every request,
+	// they're never trusted from the token
+	UserRoles   []string `json:"-"`
+	Permissions []string `json:"-"`
 }
 
 // Valid checks if the token payload is valid or not
@@ -33,6 +38,28 @@ func (payload *UserPayload) Valid() error {
 	return nil
 }
 
+// HasRole checks if the user holds the role
+func (payload *UserPayload) HasRole(role string) bool {
+	for _, v := range payload.UserRoles {
+		if v == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasPermission checks if any role of the user grants the permission
+func (payload *UserPayload) HasPermission(permission string) bool {
+	for _, v := range payload.Permissions {
+		if v == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
 // CreateToken creates a new token with payload
 func CreateToken(payload *UserPayload) (string, *UserPayload, error) {
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
@@ -0,0 +1,108 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError tell the client which field of a request is invalid & why
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// RegisterFieldNames makes the validation errors name a field the way the client sends it,
+// by it's form or json tag, instead of the name of the struct field
+func RegisterFieldNames() {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"form", "json"} {
+			name := strings.Split(field.Tag.Get(tag), ",")[0]
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+}
+
+// SendValidationError respond with every invalid field of a request, an error which isn't about
+// a field (e.g. a malformed body) is sent as a plain bad request
+func SendValidationError(c *gin.Context, err error) {
+	fieldErrors := FieldErrorsOf(err)
+	if len(fieldErrors) == 0 {
+		SendBadRequest(c, err)
+		return
+	}
+
+	c.JSON(http.StatusBadRequest, response{
+		Code:   400,
+		Status: "BAD REQUEST",
+		Error:  "some fields are invalid",
+		Errors: fieldErrors,
+	})
+	c.Abort()
+}
+
+// FieldErrorsOf list the invalid fields of a binding error, it's empty when the error isn't about a field
+func FieldErrorsOf(err error) []FieldError {
+	fieldErrors := make([]FieldError, 0)
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		for _, v := range validationErrors {
+			fieldErrors = append(fieldErrors, FieldError{
+				Field:   v.Field(),
+				Message: messageOf(v),
+			})
+		}
+		return fieldErrors
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) && typeError.Field != "" {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   typeError.Field,
+			Message: "should be " + typeError.Type.Kind().String() + ", not " + typeError.Value,
+		})
+	}
+
+	return fieldErrors
+}
+
+func messageOf(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "should be a valid email"
+	case "oneof":
+		return "should be one of " + strings.ReplaceAll(fieldError.Param(), " ", ", ")
+	case "min":
+		if fieldError.Kind() == reflect.String {
+			return "should be at least " + fieldError.Param() + " characters"
+		}
+		return "should be at least " + fieldError.Param()
+	case "max":
+		if fieldError.Kind() == reflect.String {
+			return "should be at most " + fieldError.Param() + " characters"
+		}
+		return "should be at most " + fieldError.Param()
+	default:
+		return "is invalid (" + fieldError.Tag() + ")"
+	}
+}